@@ -1,55 +1,216 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/dawgdevv/probe/internal/executor"
 	"github.com/dawgdevv/probe/internal/formatter"
 	"github.com/dawgdevv/probe/internal/loader"
 	"github.com/dawgdevv/probe/internal/service"
+	"github.com/dawgdevv/probe/pkg/models"
 	"github.com/spf13/cobra"
 )
 
+var (
+	runTimeout      time.Duration
+	runSuiteTimeout time.Duration
+	runSelection    service.Selection
+	runEnvironment  string
+	runVars         []string
+	runEnvFiles     []string
+	runSeed         int64
+)
+
+// maxConcurrent is the number of requests in flight at once, shared by all
+// suites in a run
+const maxConcurrent = 10
+
 func init() {
+	runCmd.Flags().DurationVar(&runTimeout, "timeout", 0, "Default per-request timeout (overrides the suite config, e.g. 30s)")
+	runCmd.Flags().DurationVar(&runSuiteTimeout, "suite-timeout", 0, "Deadline for the whole suite (e.g. 5m)")
+	runCmd.Flags().StringSliceVar(&runSelection.Tags, "tags", nil, "Run only tests with one of these tags (comma-separated)")
+	runCmd.Flags().StringSliceVar(&runSelection.ExcludeTags, "exclude-tags", nil, "Skip tests with any of these tags (comma-separated)")
+	runCmd.Flags().StringVar(&runSelection.Filter, "filter", "", "Run only tests whose name matches this regular expression")
+	runCmd.Flags().StringArrayVar(&runSelection.Only, "only", nil, "Run only the test with this name (repeatable)")
+	runCmd.Flags().StringVar(&runEnvironment, "env", "", "Environment from the suite's environments (or probe.env.yaml) to run against")
+	runCmd.Flags().StringArrayVar(&runVars, "var", nil, "Set a variable, overriding env and environments (key=value, repeatable)")
+	runCmd.Flags().StringArrayVar(&runEnvFiles, "env-file", nil, "Read {{env.NAME}} values from this .env file when the process environment lacks them (repeatable)")
+	runCmd.Flags().Int64Var(&runSeed, "seed", 0, "Seed for {{uuid}}, {{random.*}} and other generated values, to make runs repeatable")
 	rootCmd.AddCommand(runCmd)
 }
 
 var runCmd = &cobra.Command{
-	Use:   "run <file>",
-	Short: "Run API tests from a YAML file",
-	Args:  cobra.ExactArgs(1),
+	Use:   "run <file|dir|glob>...",
+	Short: "Run API tests from YAML files",
+	Long: "Run API tests from one or more YAML files. Directories are searched recursively\n" +
+		"and glob patterns are expanded; the suites run concurrently with a shared\n" +
+		"concurrency budget and one summary covers them all.",
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		suite, err := loader.LoadSuite(args[0])
+		paths, err := loader.FindSuites(args)
 		if err != nil {
 			fmt.Println("Errors:", err)
 			os.Exit(1)
 		}
 
-		// Create formatter for console output
-		consoleFormatter := formatter.NewConsoleFormatter()
+		suites, err := loadSuites(paths, runEnvFiles)
+		if err != nil {
+			fmt.Println("Errors:", err)
+			os.Exit(1)
+		}
 
-		// Create runner with progress callback for real-time output
-		runner := service.NewRunner(service.RunOptions{
-			MaxConcurrent: 10,
-			ProgressCallback: func(result executor.Result) {
-				consoleFormatter.PrintResult(result)
-			},
-		})
+		if err := runSelection.Validate(suites...); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
 
-		// Execute test suite
-		results, err := runner.RunSuite(suite)
+		vars, err := parseVars(runVars)
 		if err != nil {
 			fmt.Println("Error:", err)
 			os.Exit(1)
 		}
 
-		// Print summary
-		failed := service.CountFailures(results)
-		consoleFormatter.PrintSummary(len(suite.Tests), failed)
+		// Prepare every suite before starting any, so a bad --env or filter
+		// doesn't leave a run half done
+		type suiteRun struct {
+			suite       *models.TestSuite
+			results     []executor.Result
+			notSelected int
+			err         error
+		}
+		runs := make([]suiteRun, len(suites))
+		for i, suite := range suites {
+			layered, err := suite.WithEnvironment(runEnvironment, vars)
+			if err != nil {
+				if len(suites) > 1 {
+					err = fmt.Errorf("%s: %w", suite.Name, err)
+				}
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+
+			// Narrow the suite to the tests picked by --tags, --filter and
+			// friends; their dependencies come along so they can still run
+			selected, notSelected, err := runSelection.Apply(layered)
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			runs[i].suite = selected
+			runs[i].notSelected = notSelected
+		}
+
+		var seed *int64
+		if cmd.Flags().Changed("seed") {
+			seed = &runSeed
+		}
+
+		// Create formatter for console output
+		consoleFormatter := formatter.NewConsoleFormatter()
+
+		// Results from concurrent suites are printed one at a time
+		var printMu sync.Mutex
+		limiter := service.NewLimiter(maxConcurrent)
+
+		// Cancel in-flight requests on Ctrl+C instead of waiting for them
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var wg sync.WaitGroup
+		for i := range runs {
+			// Create runner with progress callback for real-time output
+			runner := service.NewRunner(service.RunOptions{
+				MaxConcurrent: maxConcurrent,
+				ProgressCallback: func(result executor.Result) {
+					printMu.Lock()
+					defer printMu.Unlock()
+					consoleFormatter.PrintResult(result)
+				},
+				Timeout:      runTimeout,
+				SuiteTimeout: runSuiteTimeout,
+				Seed:         seed,
+				Limiter:      limiter,
+			})
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				runs[i].results, runs[i].err = runner.RunSuiteContext(ctx, runs[i].suite)
+			}(i)
+		}
+		wg.Wait()
+
+		// Print one summary for every suite; setup and teardown failures
+		// and suites that could not start are reported after it
+		total, failed, skipped, notSelected := 0, 0, 0, 0
+		var suiteErrs []string
+		for _, run := range runs {
+			total += len(run.suite.Tests)
+			failed += service.CountFailures(run.results)
+			skipped += service.CountSkipped(run.results)
+			notSelected += run.notSelected
+
+			if run.err != nil {
+				msg := run.err.Error()
+				if len(suites) > 1 {
+					msg = run.suite.Name + ": " + msg
+				}
+				suiteErrs = append(suiteErrs, msg)
+			}
+		}
+
+		consoleFormatter.PrintSummary(total, failed, skipped, notSelected)
 
-		if failed > 0 {
+		for _, msg := range suiteErrs {
+			fmt.Println("Error:", msg)
+		}
+		if len(suiteErrs) > 0 || failed > 0 {
 			os.Exit(1)
 		}
 	},
 }
+
+// loadSuites loads every suite file, reporting all that fail to load. When
+// there is more than one, unnamed suites are named after their path so their
+// results can be told apart.
+func loadSuites(paths, envFiles []string) ([]*models.TestSuite, error) {
+	var suites []*models.TestSuite
+	var errs []error
+	for _, path := range paths {
+		suite, err := loader.LoadSuite(path, envFiles...)
+		if err != nil {
+			if len(paths) > 1 {
+				err = fmt.Errorf("%s: %w", path, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if suite.Name == "" && len(paths) > 1 {
+			suite.Name = strings.TrimSuffix(filepath.ToSlash(path), filepath.Ext(path))
+		}
+		suites = append(suites, suite)
+	}
+	return suites, errors.Join(errs...)
+}
+
+// parseVars parses --var key=value flags
+func parseVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (use key=value)", flag)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
@@ -1,16 +1,19 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/dawgdevv/probe/internal/formatter"
 	"github.com/dawgdevv/probe/internal/loader"
 	"github.com/dawgdevv/probe/internal/service"
 	"github.com/dawgdevv/probe/internal/storage"
 	"github.com/gin-gonic/gin"
-	"gopkg.in/yaml.v3"
 )
 
 // Handler contains all HTTP handlers for the API
@@ -131,9 +134,8 @@ func (h *Handler) CreateTestSuite(c *gin.Context) {
 		return
 	}
 
-	// Validate YAML by attempting to parse it
-	var testSuite interface{}
-	if err := yaml.Unmarshal([]byte(req.YAMLContent), &testSuite); err != nil {
+	// Validate YAML by attempting to load it as a suite
+	if _, err := loader.LoadSuiteFromString(req.YAMLContent); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid YAML: " + err.Error()})
 		return
 	}
@@ -203,6 +205,35 @@ func (h *Handler) RunTestSuite(c *gin.Context) {
 		return
 	}
 
+	// The request body optionally picks an environment, overrides variables
+	// and narrows the run by tags or test names
+	var req struct {
+		service.Selection
+		Environment string            `json:"environment"`
+		Vars        map[string]string `json:"vars"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid run options: " + err.Error()})
+		return
+	}
+
+	suite, err = suite.WithEnvironment(req.Environment, req.Vars)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := req.Selection.Validate(suite); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suite, notSelected, err := req.Selection.Apply(suite)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Create test run record
 	testRun, err := h.store.CreateTestRun(id, len(suite.Tests))
 	if err != nil {
@@ -215,10 +246,13 @@ func (h *Handler) RunTestSuite(c *gin.Context) {
 		MaxConcurrent: 10,
 	})
 
-	results, err := runner.RunSuite(suite)
-	if err != nil {
+	results, err := runner.RunSuiteContext(c.Request.Context(), suite)
+
+	// A failed setup or teardown still produces results to store
+	var hookErr *service.HookError
+	if err != nil && !errors.As(err, &hookErr) {
 		// Mark run as error
-		h.store.CompleteTestRun(testRun.ID, "error", 0, 0)
+		h.store.CompleteTestRun(testRun.ID, "error", 0, 0, 0)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -226,17 +260,29 @@ func (h *Handler) RunTestSuite(c *gin.Context) {
 	// Save individual test results
 	passed := 0
 	failed := 0
+	skipped := 0
 	for _, result := range results {
 		errorMsg := ""
-		if result.Error != nil {
+		switch {
+		case result.Skipped:
+			errorMsg = result.SkipReason
+			skipped++
+		case result.Error != nil:
 			errorMsg = result.Error.Error()
 			failed++
-		} else {
+		default:
 			passed++
 		}
 
+		failures := ""
+		if len(result.Failures) > 0 {
+			if b, err := json.Marshal(result.Failures); err == nil {
+				failures = string(b)
+			}
+		}
+
 		durationMs := result.Duration.Milliseconds()
-		if err := h.store.SaveTestResult(testRun.ID, result.Name, result.Passed, result.StatusCode, errorMsg, durationMs); err != nil {
+		if err := h.store.SaveTestResult(testRun.ID, result.Name, result.Passed, result.Skipped, result.StatusCode, errorMsg, failures, durationMs); err != nil {
 			fmt.Printf("Warning: failed to save test result: %v\n", err)
 		}
 	}
@@ -246,18 +292,28 @@ func (h *Handler) RunTestSuite(c *gin.Context) {
 	if failed > 0 {
 		status = "failed"
 	}
-	if err := h.store.CompleteTestRun(testRun.ID, status, passed, failed); err != nil {
+	if err != nil {
+		status = "error"
+	}
+	if err := h.store.CompleteTestRun(testRun.ID, status, passed, failed, skipped); err != nil {
 		fmt.Printf("Warning: failed to complete test run: %v\n", err)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"run_id":       testRun.ID,
-		"status":       status,
-		"total_tests":  len(suite.Tests),
-		"passed_tests": passed,
-		"failed_tests": failed,
-		"results":      results,
-	})
+	response := gin.H{
+		"run_id":        testRun.ID,
+		"status":        status,
+		"total_tests":   len(suite.Tests),
+		"passed_tests":  passed,
+		"failed_tests":  failed,
+		"skipped_tests": skipped,
+		"not_selected":  notSelected,
+		"results":       formatter.NewJSONFormatter().Format(results, notSelected).Results,
+	}
+	if err != nil {
+		response["error"] = err.Error()
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // --- Test Run Handlers ---
@@ -0,0 +1,63 @@
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// AssertBody checks the raw response text and returns every check that failed
+func AssertBody(body []byte, exp *models.BodyExpect) []Failure {
+	text := string(body)
+	var failures []Failure
+
+	if exp.Exact != nil && text != *exp.Exact {
+		failures = append(failures, Failure{
+			Target:   TargetBody,
+			Operator: "exact",
+			Expected: *exp.Exact,
+			Actual:   Truncate(text),
+			Message:  fmt.Sprintf("expected %q, got %q", *exp.Exact, Truncate(text)),
+		})
+	}
+
+	for _, want := range exp.Contains {
+		if !strings.Contains(text, want) {
+			failures = append(failures, Failure{Target: TargetBody, Operator: "contains", Expected: want, Message: fmt.Sprintf("does not contain %q", want)})
+		}
+	}
+
+	for _, unwanted := range exp.NotContains {
+		if strings.Contains(text, unwanted) {
+			failures = append(failures, Failure{Target: TargetBody, Operator: "not_contains", Expected: unwanted, Message: fmt.Sprintf("contains %q", unwanted)})
+		}
+	}
+
+	if exp.Regex != "" {
+		re, err := regexp.Compile(exp.Regex)
+		switch {
+		case err != nil:
+			failures = append(failures, Failure{Target: TargetBody, Operator: "regex", Expected: exp.Regex, Message: fmt.Sprintf("invalid regex %q: %v", exp.Regex, err)})
+		case !re.Match(body):
+			failures = append(failures, Failure{Target: TargetBody, Operator: "regex", Expected: exp.Regex, Message: fmt.Sprintf("does not match /%s/", exp.Regex)})
+		}
+	}
+
+	return failures
+}
+
+// Truncate shortens long bodies for error messages to their first 200
+// characters, never splitting a multi-byte one
+func Truncate(s string) string {
+	const max = 200
+	n := 0
+	for i := range s {
+		if n == max {
+			return s[:i] + "..."
+		}
+		n++
+	}
+	return s
+}
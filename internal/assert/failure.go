@@ -0,0 +1,98 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Assertion targets reported in Failure.Target
+const (
+	TargetStatus   = "status"
+	TargetJSON     = "json"
+	TargetHeader   = "header"
+	TargetCookie   = "cookie"
+	TargetJWT      = "jwt"
+	TargetRedirect = "redirect"
+	TargetBody     = "body"
+	TargetDuration = "duration"
+)
+
+// Failure describes a single expectation that did not hold
+type Failure struct {
+	Target   string      `json:"target"`
+	Path     string      `json:"path,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+	Message  string      `json:"message"`
+}
+
+// Error formats the failure the way probe has always reported it
+func (f Failure) Error() string {
+	switch f.Target {
+	case TargetJSON:
+		if f.Path == "" {
+			return f.Message
+		}
+		return fmt.Sprintf("assertion failed at %s: %s", f.Path, f.Message)
+	case TargetHeader:
+		return fmt.Sprintf("header %s: %s", f.Path, f.Message)
+	case TargetCookie:
+		return fmt.Sprintf("cookie %s: %s", f.Path, f.Message)
+	case TargetJWT:
+		if f.Path == "" {
+			return "jwt: " + f.Message
+		}
+		return fmt.Sprintf("jwt %s: %s", f.Path, f.Message)
+	case TargetRedirect:
+		return "redirect: " + f.Message
+	case TargetBody:
+		return "body: " + f.Message
+	}
+	return f.Message
+}
+
+// Failures joins several failures into one error
+type Failures []Failure
+
+func (fs Failures) Error() string {
+	if len(fs) == 1 {
+		return fs[0].Error()
+	}
+	msgs := make([]string, len(fs))
+	for i, f := range fs {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%d assertions failed: %s", len(fs), strings.Join(msgs, "; "))
+}
+
+// Redact returns a copy of the failure with mask applied to its message and
+// to any strings in its expected and actual values
+func (f Failure) Redact(mask func(string) string) Failure {
+	f.Path = mask(f.Path)
+	f.Message = mask(f.Message)
+	f.Expected = RedactValue(f.Expected, mask)
+	f.Actual = RedactValue(f.Actual, mask)
+	return f
+}
+
+// RedactValue applies mask to the strings inside a decoded JSON value
+func RedactValue(v interface{}, mask func(string) string) interface{} {
+	switch val := v.(type) {
+	case string:
+		return mask(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, elem := range val {
+			out[i] = RedactValue(elem, mask)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, elem := range val {
+			out[mask(k)] = RedactValue(elem, mask)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AssertHeaders checks response headers against rules keyed by header name
+// and returns every header that did not match. Names are case-insensitive;
+// multiple values for one header are joined with ", " before matching.
+func AssertHeaders(headers http.Header, rules map[string]interface{}) []Failure {
+	return assertValues(TargetHeader, rules, func(name string) (string, bool) {
+		values := headers.Values(name)
+		return strings.Join(values, ", "), len(values) > 0
+	})
+}
+
+// AssertCookies checks the cookies a response sets against rules keyed by
+// cookie name. When a cookie is set more than once, the last value counts.
+func AssertCookies(cookies []*http.Cookie, rules map[string]interface{}) []Failure {
+	return assertValues(TargetCookie, rules, func(name string) (string, bool) {
+		value, found := "", false
+		for _, c := range cookies {
+			if c.Name == name {
+				value, found = c.Value, true
+			}
+		}
+		return value, found
+	})
+}
+
+// assertValues checks the named values that lookup finds against rules,
+// each an exact value or a matcher
+func assertValues(target string, rules map[string]interface{}, lookup func(name string) (string, bool)) []Failure {
+	var failures []Failure
+	for _, name := range sortedKeys(rules) {
+		expected := rules[name]
+		actual, found := lookup(name)
+
+		m, isMatcher, err := parseMatcher(expected)
+		if err != nil {
+			failures = append(failures, Failure{Target: target, Path: name, Message: "invalid matcher: " + err.Error()})
+			continue
+		}
+
+		if isMatcher {
+			if err := m.match(actual, found); err != nil {
+				failures = append(failures, Failure{Target: target, Path: name, Operator: m.op, Expected: m.expected(), Actual: actual, Message: err.Error()})
+			}
+			continue
+		}
+
+		want := fmt.Sprint(expected)
+		if !found {
+			failures = append(failures, Failure{Target: target, Path: name, Operator: "eq", Expected: want, Message: "not present"})
+			continue
+		}
+		if actual != want {
+			failures = append(failures, Failure{
+				Target:   target,
+				Path:     name,
+				Operator: "eq",
+				Expected: want,
+				Actual:   actual,
+				Message:  fmt.Sprintf("expected %q, got %q", want, actual),
+			})
+		}
+	}
+	return failures
+}
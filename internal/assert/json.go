@@ -3,98 +3,124 @@ package assert
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/dawgdevv/probe/internal/jsonpath"
 )
 
 func extractvalue(data interface{}, path string) (interface{}, error) {
-	if path == "$.length" {
-		if arr, ok := data.([]interface{}); ok {
-			return len(arr), nil
-		}
-		return nil, fmt.Errorf("$.length applied to non-array")
+	p, err := jsonpath.Parse(path)
+	if err != nil {
+		return nil, err
 	}
-
-	parts := strings.Split(path, ".")
-	current := data
-
-	for _, part := range parts {
-		obj, ok := current.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("invalid path: %s", path)
-		}
-
-		val, exists := obj[part]
-		if !exists {
-			return nil, fmt.Errorf("field %s not found", path)
-		}
-		current = val
-	}
-
-	return current, nil
+	return p.Evaluate(data)
 }
 
-func isComparison(v string) bool {
-	return strings.HasPrefix(v, ">") || strings.HasPrefix(v, "<")
-}
-
-func compare(actual interface{}, expStr string) error {
-	var actualNum float64
+// ExtractJSON parses body and returns the value found at path
+func ExtractJSON(body []byte, path string) (interface{}, error) {
+	var data interface{}
 
-	switch v := actual.(type) {
-	case float64:
-		actualNum = v
-	case int:
-		actualNum = float64(v)
-	case int64:
-		actualNum = float64(v)
-	default:
-		return fmt.Errorf("comparison on non-number")
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("invalid json response")
 	}
+	return extractvalue(data, path)
+}
 
-	rule := strings.TrimSpace(expStr)
-	operator := rule[:1]
-	value := rule[1:]
-	var expected float64
-	fmt.Sscanf(value, "%f", &expected)
-
-	switch operator {
-	case ">":
-		if actualNum <= expected {
-			return fmt.Errorf("%v is not > %v", actualNum, expected)
+// legacyComparison parses the older string form of a numeric comparison
+// (">5", "<=10"). Strings that merely start with "<" or ">" but are not
+// followed by a number are not comparisons.
+func legacyComparison(v string) (*matcher, bool) {
+	rule := strings.TrimSpace(v)
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(rule, op) {
+			continue
 		}
-	case "<":
-		if actualNum >= expected {
-			return fmt.Errorf("%v is not < %v", actualNum, expected)
+		n, err := strconv.ParseFloat(strings.TrimSpace(rule[len(op):]), 64)
+		if err != nil {
+			return nil, false
 		}
+		return &matcher{op: opAliases[op], value: n}, true
 	}
-
-	return nil
-
+	return nil, false
 }
 
-func AssertJSON(body []byte, rules map[string]interface{}) error {
+// AssertJSON checks each path in rules against the response body and
+// returns every assertion that failed. Expected values are matched literally
+// unless they are a matcher ({ op: gte, value: 3 }) or a legacy comparison
+// string such as ">5".
+func AssertJSON(body []byte, rules map[string]interface{}) []Failure {
 	var data interface{}
 
 	if err := json.Unmarshal(body, &data); err != nil {
-		return fmt.Errorf("invalid json response")
+		return []Failure{{Target: TargetJSON, Message: "invalid json response"}}
 	}
-	for path, expected := range rules {
-		actual, err := extractvalue(data, path)
+
+	var failures []Failure
+	for _, path := range sortedKeys(rules) {
+		expected := rules[path]
+
+		p, err := jsonpath.Parse(path)
 		if err != nil {
-			return err
+			failures = append(failures, Failure{Target: TargetJSON, Path: path, Message: err.Error()})
+			continue
 		}
 
-		if expStr, ok := expected.(string); ok && isComparison(expStr) {
-			if err := compare(actual, expStr); err != nil {
-				return fmt.Errorf("assertion failed at %s: %v", path, err)
+		m, isMatcher, err := parseMatcher(expected)
+		if err != nil {
+			failures = append(failures, Failure{Target: TargetJSON, Path: path, Message: "invalid matcher: " + err.Error()})
+			continue
+		}
+		if !isMatcher {
+			if expStr, ok := expected.(string); ok {
+				m, isMatcher = legacyComparison(expStr)
 			}
+		}
 
+		actual, err := p.Evaluate(data)
+
+		if isMatcher {
+			found := err == nil
+			if m.op == "exists" || m.op == "notExists" {
+				// A wildcard or filter that selects nothing does not exist either
+				if list, ok := actual.([]interface{}); ok && !p.Definite() && len(list) == 0 {
+					found = false
+				}
+			} else if err != nil {
+				failures = append(failures, Failure{Target: TargetJSON, Path: path, Operator: m.op, Expected: m.expected(), Message: err.Error()})
+				continue
+			}
+			if err := m.match(actual, found); err != nil {
+				failures = append(failures, Failure{Target: TargetJSON, Path: path, Operator: m.op, Expected: m.expected(), Actual: actual, Message: err.Error()})
+			}
 			continue
 		}
 
+		if err != nil {
+			failures = append(failures, Failure{Target: TargetJSON, Path: path, Operator: "eq", Expected: expected, Message: err.Error()})
+			continue
+		}
 		if fmt.Sprint(actual) != fmt.Sprint(expected) {
-			return fmt.Errorf("assertion failed at %s: expected %v, got %v", path, expected, actual)
+			failures = append(failures, Failure{
+				Target:   TargetJSON,
+				Path:     path,
+				Operator: "eq",
+				Expected: expected,
+				Actual:   actual,
+				Message:  fmt.Sprintf("expected %v, got %v", expected, actual),
+			})
 		}
 	}
-	return nil
+	return failures
+}
+
+// sortedKeys returns map keys in a stable order so failures are reported consistently
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,60 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dawgdevv/probe/internal/jwt"
+)
+
+// AssertJWT decodes token and checks its algorithm, its signature when key
+// is set, and its claims against rules (paths into the claims, as in
+// AssertJSON). The signature is checked with algorithm, or without one an
+// algorithm for the key's type. A verified token must also be within its
+// exp and nbf.
+func AssertJWT(token, algorithm, key string, rules map[string]interface{}) []Failure {
+	t, err := jwt.Parse(token)
+	if err != nil {
+		return []Failure{{Target: TargetJWT, Message: err.Error()}}
+	}
+
+	var failures []Failure
+	algMatches := algorithm == "" || strings.EqualFold(t.Algorithm(), algorithm)
+	if !algMatches {
+		failures = append(failures, Failure{
+			Target:   TargetJWT,
+			Path:     "alg",
+			Operator: "eq",
+			Expected: algorithm,
+			Actual:   t.Algorithm(),
+			Message:  fmt.Sprintf("expected %s, got %s", algorithm, t.Algorithm()),
+		})
+	}
+
+	if key != "" && algMatches {
+		if err := t.Verify(algorithm, key); err != nil {
+			failures = append(failures, Failure{Target: TargetJWT, Path: "signature", Message: err.Error()})
+		} else {
+			failures = append(failures, checkTimes(t.Claims, time.Now())...)
+		}
+	}
+
+	for _, f := range AssertJSON(t.Payload, rules) {
+		f.Target = TargetJWT
+		failures = append(failures, f)
+	}
+	return failures
+}
+
+// checkTimes reports a token used after its exp or before its nbf
+func checkTimes(claims map[string]interface{}, now time.Time) []Failure {
+	var failures []Failure
+	if exp, ok := claims["exp"].(float64); ok && !now.Before(time.Unix(int64(exp), 0)) {
+		failures = append(failures, Failure{Target: TargetJWT, Path: "exp", Message: "token expired at " + time.Unix(int64(exp), 0).UTC().Format(time.RFC3339)})
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		failures = append(failures, Failure{Target: TargetJWT, Path: "nbf", Message: "token not valid until " + time.Unix(int64(nbf), 0).UTC().Format(time.RFC3339)})
+	}
+	return failures
+}
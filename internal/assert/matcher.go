@@ -0,0 +1,381 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// matcher is a structured assertion written in YAML as { op: <name>, value: <v> }
+type matcher struct {
+	op    string
+	value interface{}
+	min   interface{}
+	max   interface{}
+}
+
+// opAliases maps symbolic and alternate operator spellings to their canonical names
+var opAliases = map[string]string{
+	"==": "eq", "equals": "eq",
+	"!=": "ne", "neq": "ne", "notEquals": "ne",
+	">": "gt", ">=": "gte", "<": "lt", "<=": "lte",
+	"matches": "regex",
+	"in":      "oneOf",
+	"exist":   "exists", "notExist": "notExists",
+	"len": "length",
+}
+
+// parseMatcher recognises a map with an "op" key as a matcher. Any other
+// expected value is compared literally.
+func parseMatcher(expected interface{}) (*matcher, bool, error) {
+	spec, ok := expected.(map[string]interface{})
+	if !ok {
+		return nil, false, nil
+	}
+	rawOp, ok := spec["op"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	op, ok := rawOp.(string)
+	if !ok {
+		return nil, true, fmt.Errorf("matcher op must be a string, got %v", rawOp)
+	}
+	if canonical, ok := opAliases[op]; ok {
+		op = canonical
+	}
+
+	m := &matcher{op: op, value: spec["value"], min: spec["min"], max: spec["max"]}
+	if err := m.validate(); err != nil {
+		return nil, true, err
+	}
+	return m, true, nil
+}
+
+func (m *matcher) validate() error {
+	switch m.op {
+	case "eq", "ne", "contains", "notContains":
+	case "gt", "gte", "lt", "lte":
+		if _, ok := toNumber(m.value); !ok {
+			return fmt.Errorf("%s needs a numeric value, got %v", m.op, m.value)
+		}
+	case "between":
+		if list, ok := m.value.([]interface{}); ok && len(list) == 2 {
+			m.min, m.max = list[0], list[1]
+		}
+		_, minOK := toNumber(m.min)
+		_, maxOK := toNumber(m.max)
+		if !minOK || !maxOK {
+			return fmt.Errorf("between needs numeric min and max, e.g. value: [1, 10]")
+		}
+	case "regex":
+		pattern, ok := textValue(m.value)
+		if !ok {
+			return fmt.Errorf("regex needs a string pattern")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		m.value = re
+	case "startsWith", "endsWith":
+		text, ok := textValue(m.value)
+		if !ok {
+			return fmt.Errorf("%s needs a string value", m.op)
+		}
+		m.value = text
+	case "oneOf":
+		if _, ok := m.value.([]interface{}); !ok {
+			return fmt.Errorf("oneOf needs a list of values")
+		}
+	case "type":
+		switch m.value {
+		case "string", "number", "integer", "array", "object", "null", "bool", "boolean":
+		default:
+			return fmt.Errorf("unknown type %v (want string, number, integer, array, object, null or bool)", m.value)
+		}
+	case "exists", "notExists", "empty", "notEmpty":
+	case "length", "minLength", "maxLength":
+		if _, ok := toNumber(m.value); !ok {
+			return fmt.Errorf("%s needs a numeric value, got %v", m.op, m.value)
+		}
+	default:
+		return fmt.Errorf("unknown matcher op %q", m.op)
+	}
+	return nil
+}
+
+// match checks the actual value against the matcher. found is false when
+// the path did not resolve, which only exists and notExists accept.
+func (m *matcher) match(actual interface{}, found bool) error {
+	switch m.op {
+	case "exists":
+		if !found {
+			return fmt.Errorf("expected field to exist")
+		}
+		return nil
+	case "notExists":
+		if found {
+			return fmt.Errorf("expected field not to exist, got %v", actual)
+		}
+		return nil
+	}
+
+	if !found {
+		return fmt.Errorf("field not found")
+	}
+
+	switch m.op {
+	case "eq":
+		if !equal(actual, m.value) {
+			return fmt.Errorf("expected %v, got %v", m.value, actual)
+		}
+	case "ne":
+		if equal(actual, m.value) {
+			return fmt.Errorf("expected value other than %v", m.value)
+		}
+
+	case "gt", "gte", "lt", "lte":
+		n, ok := toNumber(actual)
+		if !ok {
+			return fmt.Errorf("%s on non-number %v", m.op, actual)
+		}
+		want, _ := toNumber(m.value)
+		if !compareNumbers(n, want, m.op) {
+			return fmt.Errorf("%v is not %s %v", actual, opSymbol(m.op), m.value)
+		}
+
+	case "between":
+		n, ok := toNumber(actual)
+		if !ok {
+			return fmt.Errorf("between on non-number %v", actual)
+		}
+		lo, _ := toNumber(m.min)
+		hi, _ := toNumber(m.max)
+		if n < lo || n > hi {
+			return fmt.Errorf("%v is not between %v and %v", actual, m.min, m.max)
+		}
+
+	case "regex":
+		s, ok := actual.(string)
+		if !ok {
+			s = fmt.Sprint(actual)
+		}
+		re := m.value.(*regexp.Regexp)
+		if !re.MatchString(s) {
+			return fmt.Errorf("%q does not match /%s/", s, re)
+		}
+
+	case "contains", "notContains":
+		has, err := contains(actual, m.value)
+		if err != nil {
+			return err
+		}
+		if has != (m.op == "contains") {
+			if has {
+				return fmt.Errorf("%v contains %v", actual, m.value)
+			}
+			return fmt.Errorf("%v does not contain %v", actual, m.value)
+		}
+
+	case "startsWith", "endsWith":
+		s, ok := actual.(string)
+		if !ok {
+			return fmt.Errorf("%s on non-string %v", m.op, actual)
+		}
+		want := m.value.(string)
+		if m.op == "startsWith" && !strings.HasPrefix(s, want) {
+			return fmt.Errorf("%q does not start with %q", s, want)
+		}
+		if m.op == "endsWith" && !strings.HasSuffix(s, want) {
+			return fmt.Errorf("%q does not end with %q", s, want)
+		}
+
+	case "oneOf":
+		for _, option := range m.value.([]interface{}) {
+			if equal(actual, option) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not one of %v", actual, m.value)
+
+	case "type":
+		if got := jsonType(actual); !typeMatches(got, actual, m.value.(string)) {
+			return fmt.Errorf("expected type %s, got %s", m.value, got)
+		}
+
+	case "empty", "notEmpty":
+		n, ok := lengthOf(actual)
+		isEmpty := actual == nil || (ok && n == 0)
+		if m.op == "empty" && !isEmpty {
+			return fmt.Errorf("expected empty, got %v", actual)
+		}
+		if m.op == "notEmpty" && isEmpty {
+			return fmt.Errorf("expected a non-empty value")
+		}
+
+	case "length", "minLength", "maxLength":
+		n, ok := lengthOf(actual)
+		if !ok {
+			return fmt.Errorf("%s on %s", m.op, jsonType(actual))
+		}
+		want, _ := toNumber(m.value)
+		switch {
+		case m.op == "length" && float64(n) != want:
+			return fmt.Errorf("expected length %v, got %d", m.value, n)
+		case m.op == "minLength" && float64(n) < want:
+			return fmt.Errorf("expected length >= %v, got %d", m.value, n)
+		case m.op == "maxLength" && float64(n) > want:
+			return fmt.Errorf("expected length <= %v, got %d", m.value, n)
+		}
+	}
+
+	return nil
+}
+
+// expected returns the value the matcher compares against, for reporting
+func (m *matcher) expected() interface{} {
+	switch m.op {
+	case "between":
+		return []interface{}{m.min, m.max}
+	case "regex":
+		return m.value.(*regexp.Regexp).String()
+	}
+	return m.value
+}
+
+func opSymbol(op string) string {
+	switch op {
+	case "gt":
+		return ">"
+	case "gte":
+		return ">="
+	case "lt":
+		return "<"
+	case "lte":
+		return "<="
+	}
+	return op
+}
+
+func compareNumbers(a, b float64, op string) bool {
+	switch op {
+	case "gt":
+		return a > b
+	case "gte":
+		return a >= b
+	case "lt":
+		return a < b
+	case "lte":
+		return a <= b
+	}
+	return false
+}
+
+// equal compares JSON and YAML values, treating all numeric types alike
+func equal(a, b interface{}) bool {
+	if an, ok := toNumber(a); ok {
+		if bn, ok := toNumber(b); ok {
+			return an == bn
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func contains(actual, want interface{}) (bool, error) {
+	switch v := actual.(type) {
+	case string:
+		return strings.Contains(v, fmt.Sprint(want)), nil
+	case []interface{}:
+		for _, elem := range v {
+			if equal(elem, want) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		_, ok := v[fmt.Sprint(want)]
+		return ok, nil
+	}
+	return false, fmt.Errorf("contains on %s", jsonType(actual))
+}
+
+func lengthOf(v interface{}) (int, bool) {
+	switch val := v.(type) {
+	case string:
+		return len([]rune(val)), true
+	case []interface{}:
+		return len(val), true
+	case map[string]interface{}:
+		return len(val), true
+	}
+	return 0, false
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toNumber(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeMatches(got string, actual interface{}, want string) bool {
+	switch want {
+	case "boolean":
+		want = "bool"
+	case "integer":
+		n, ok := toNumber(actual)
+		return ok && n == float64(int64(n))
+	}
+	return got == want
+}
+
+// textValue returns a string matcher value. Variables holding text that
+// looks like a number or bool arrive as those, and are turned back.
+func textValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case json.Number:
+		return string(val), true
+	case bool:
+		return fmt.Sprint(val), true
+	}
+	return "", false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package assert
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMatcherValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   map[string]interface{}
+		actual interface{}
+		pass   bool
+	}{
+		{"gte float", map[string]interface{}{"op": "gte", "value": 3.0}, 5.0, true},
+		{"gte json.Number", map[string]interface{}{"op": "gte", "value": json.Number("3")}, 5.0, true},
+		{"lt json.Number", map[string]interface{}{"op": "lt", "value": json.Number("3")}, 5.0, false},
+		{"between json.Number", map[string]interface{}{"op": "between", "value": []interface{}{json.Number("1"), json.Number("10")}}, 4.0, true},
+		{"length json.Number", map[string]interface{}{"op": "length", "value": json.Number("2")}, []interface{}{1.0, 2.0}, true},
+		{"minLength json.Number", map[string]interface{}{"op": "minLength", "value": json.Number("3")}, "ab", false},
+		{"eq json.Number", map[string]interface{}{"op": "eq", "value": json.Number("42")}, 42.0, true},
+		{"oneOf json.Number", map[string]interface{}{"op": "oneOf", "value": []interface{}{json.Number("1"), json.Number("2")}}, 2.0, true},
+		{"startsWith json.Number", map[string]interface{}{"op": "startsWith", "value": json.Number("12")}, "123", true},
+		{"endsWith bool", map[string]interface{}{"op": "endsWith", "value": true}, "is true", true},
+		{"regex json.Number", map[string]interface{}{"op": "regex", "value": json.Number("42")}, "a42b", true},
+		{"type of json.Number", map[string]interface{}{"op": "type", "value": "integer"}, json.Number("7"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok, err := parseMatcher(tt.spec)
+			if !ok || err != nil {
+				t.Fatalf("parseMatcher(%v) = %v, %v", tt.spec, ok, err)
+			}
+			if err := m.match(tt.actual, true); (err == nil) != tt.pass {
+				t.Errorf("match(%v) error = %v, want pass %v", tt.actual, err, tt.pass)
+			}
+		})
+	}
+}
+
+func TestMatcherRejectsNonNumbers(t *testing.T) {
+	for _, value := range []interface{}{"three", json.Number("x"), nil} {
+		if _, _, err := parseMatcher(map[string]interface{}{"op": "gte", "value": value}); err == nil {
+			t.Errorf("gte with %v: expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package assert
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AssertRedirect checks target, where a response redirected to, against
+// expected: an exact URL, a path compared with the target's path and query,
+// or a matcher on the whole URL. target is nil when there was no redirect.
+func AssertRedirect(target *url.URL, expected interface{}) []Failure {
+	actual := ""
+	if target != nil {
+		actual = target.String()
+	}
+
+	m, isMatcher, err := parseMatcher(expected)
+	if err != nil {
+		return []Failure{{Target: TargetRedirect, Message: "invalid matcher: " + err.Error()}}
+	}
+	if isMatcher {
+		if err := m.match(actual, target != nil); err != nil {
+			return []Failure{{Target: TargetRedirect, Operator: m.op, Expected: m.expected(), Actual: actual, Message: err.Error()}}
+		}
+		return nil
+	}
+
+	want := fmt.Sprint(expected)
+	if target == nil {
+		return []Failure{{Target: TargetRedirect, Operator: "eq", Expected: want, Message: "response did not redirect"}}
+	}
+	if strings.HasPrefix(want, "/") {
+		actual = target.RequestURI()
+	}
+	if actual != want {
+		return []Failure{{
+			Target:   TargetRedirect,
+			Operator: "eq",
+			Expected: want,
+			Actual:   actual,
+			Message:  fmt.Sprintf("expected %q, got %q", want, actual),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ParseDotEnv reads a .env file: KEY=value lines, with optional "export"
+// prefixes, # comments and single- or double-quoted values. Double-quoted
+// values may use \n, \t, \" and \\ escapes.
+func ParseDotEnv(data []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			end := closingQuote(value)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", lineNo)
+			}
+			if value[0] == '"' {
+				value = unescape(value[1:end])
+			} else {
+				value = value[1:end]
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			// Unquoted values end at an inline comment
+			value = strings.TrimSpace(value[:i])
+		}
+
+		vars[key] = value
+	}
+
+	return vars, scanner.Err()
+}
+
+// closingQuote returns the index of the quote ending the value that s
+// opens with, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[0] == '"' && s[i] == '\\':
+			i++
+		case s[i] == s[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
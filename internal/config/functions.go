@@ -0,0 +1,126 @@
+package config
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// function is a built-in usable in {{ }} expressions. A piped value arrives
+// as the last argument.
+type function func(e *Evaluator, args []interface{}) (interface{}, error)
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"uuid": fixed(0, func(e *Evaluator, _ []interface{}) (interface{}, error) {
+			var b [16]byte
+			for i := range b {
+				b[i] = byte(e.rand.UintN(256))
+			}
+			b[6] = b[6]&0x0f | 0x40 // version 4
+			b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+			return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+		}),
+
+		// Time
+		"now": fixed(0, func(e *Evaluator, _ []interface{}) (interface{}, error) {
+			return e.now(), nil
+		}),
+		"rfc3339": timeFunc(func(t time.Time) interface{} { return t.Format(time.RFC3339) }),
+		"unix":    timeFunc(func(t time.Time) interface{} { return t.Unix() }),
+		"unixms":  timeFunc(func(t time.Time) interface{} { return t.UnixMilli() }),
+		"date":    timeFunc(func(t time.Time) interface{} { return t.Format("2006-01-02") }),
+		"format": fixed(2, func(_ *Evaluator, args []interface{}) (interface{}, error) {
+			t, ok := args[1].(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("format needs a time, got %v", args[1])
+			}
+			return t.Format(toString(args[0])), nil
+		}),
+
+		// Random values
+		"random.int": fixed(2, func(e *Evaluator, args []interface{}) (interface{}, error) {
+			lo, lok := args[0].(int64)
+			hi, hok := args[1].(int64)
+			if !lok || !hok || hi < lo {
+				return nil, fmt.Errorf("random.int needs integer bounds min <= max, got %v and %v", args[0], args[1])
+			}
+			return lo + e.rand.Int64N(hi-lo+1), nil
+		}),
+		"random.string": func(e *Evaluator, args []interface{}) (interface{}, error) {
+			n := int64(12)
+			if len(args) > 1 {
+				return nil, fmt.Errorf("random.string takes at most 1 argument, got %d", len(args))
+			}
+			if len(args) == 1 {
+				var ok bool
+				if n, ok = args[0].(int64); !ok || n < 0 {
+					return nil, fmt.Errorf("random.string needs a length, got %v", args[0])
+				}
+			}
+			return randomString(e, int(n)), nil
+		},
+		"random.email": fixed(0, func(e *Evaluator, _ []interface{}) (interface{}, error) {
+			return "user_" + randomString(e, 10) + "@example.com", nil
+		}),
+
+		// Encoding and hashing
+		"base64":    stringFunc(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+		"base64url": stringFunc(func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }),
+		"urlencode": stringFunc(url.QueryEscape),
+		"sha256":    stringFunc(func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) }),
+		"sha1":      stringFunc(func(s string) string { sum := sha1.Sum([]byte(s)); return hex.EncodeToString(sum[:]) }),
+		"md5":       stringFunc(func(s string) string { sum := md5.Sum([]byte(s)); return hex.EncodeToString(sum[:]) }),
+
+		// Strings
+		"upper": stringFunc(strings.ToUpper),
+		"lower": stringFunc(strings.ToLower),
+		"trim":  stringFunc(strings.TrimSpace),
+	}
+}
+
+// fixed wraps fn with a check that it receives exactly n arguments
+func fixed(n int, fn function) function {
+	return func(e *Evaluator, args []interface{}) (interface{}, error) {
+		if len(args) != n {
+			return nil, fmt.Errorf("expected %d arguments, got %d", n, len(args))
+		}
+		return fn(e, args)
+	}
+}
+
+// stringFunc adapts a string transformation taking one argument
+func stringFunc(fn func(string) string) function {
+	return fixed(1, func(_ *Evaluator, args []interface{}) (interface{}, error) {
+		return fn(toString(args[0])), nil
+	})
+}
+
+// timeFunc adapts a time formatter taking one argument
+func timeFunc(fn func(time.Time) interface{}) function {
+	return fixed(1, func(_ *Evaluator, args []interface{}) (interface{}, error) {
+		t, ok := args[0].(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("expected a time, got %v", args[0])
+		}
+		return fn(t), nil
+	})
+}
+
+const alphanumeric = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func randomString(e *Evaluator, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphanumeric[e.rand.IntN(len(alphanumeric))]
+	}
+	return string(b)
+}
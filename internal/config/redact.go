@@ -0,0 +1,79 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces secret values in output
+const Mask = "****"
+
+// Redactor masks secret values wherever they appear in text, including
+// URL-encoded forms of them. It is safe for concurrent use.
+type Redactor struct {
+	mu       sync.RWMutex
+	values   map[string]bool
+	replacer *strings.Replacer
+}
+
+// NewRedactor creates a redactor for the given secret values
+func NewRedactor(values ...string) *Redactor {
+	r := &Redactor{values: make(map[string]bool)}
+	r.Add(values...)
+	return r
+}
+
+// Add registers more secret values; empty values are ignored
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		for _, form := range []string{v, url.QueryEscape(v), url.PathEscape(v)} {
+			if !r.values[form] {
+				r.values[form] = true
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return
+	}
+
+	// Longer values first, so a secret containing another is masked whole
+	forms := make([]string, 0, len(r.values))
+	for v := range r.values {
+		forms = append(forms, v)
+	}
+	sort.Slice(forms, func(i, j int) bool {
+		if len(forms[i]) != len(forms[j]) {
+			return len(forms[i]) > len(forms[j])
+		}
+		return forms[i] < forms[j]
+	})
+
+	pairs := make([]string, 0, 2*len(forms))
+	for _, v := range forms {
+		pairs = append(pairs, v, Mask)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact returns s with every secret value replaced by Mask
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var refRe = regexp.MustCompile(`\{\{\s*(?:env\.(\w+)|file:([^{}]+?))\s*\}\}`)
+
+// References resolves the namespaced references that point outside the
+// suite: {{env.NAME}} reads a process environment variable, falling back to
+// DotEnv, and {{file:path}} reads a file relative to Dir. Plain {{name}}
+// variables are left for SubstituteString.
+type References struct {
+	// Local allows the references, for suites read from this machine's
+	// filesystem. Suites from elsewhere, such as the API, may not read its
+	// environment or files.
+	Local bool
+
+	// DotEnv holds variables from .env files, used when the process
+	// environment doesn't set them
+	DotEnv map[string]string
+
+	// Dir is the directory file references are relative to
+	Dir string
+}
+
+// Resolve replaces every reference in input
+func (r References) Resolve(input string) (string, error) {
+	if !strings.Contains(input, "{{") {
+		return input, nil
+	}
+
+	var firstErr error
+	result := refRe.ReplaceAllStringFunc(input, func(match string) string {
+		m := refRe.FindStringSubmatch(match)
+		var (
+			val string
+			err error
+		)
+		if !r.Local {
+			err = fmt.Errorf("%s: env and file references are only allowed in suite files", match)
+		} else if m[1] != "" {
+			val, err = r.env(m[1])
+		} else {
+			val, err = r.file(m[2])
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return match
+		}
+		return val
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+func (r References) env(name string) (string, error) {
+	if val, ok := os.LookupEnv(name); ok {
+		return val, nil
+	}
+	if val, ok := r.DotEnv[name]; ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", name)
+}
+
+// file returns the file's contents without a trailing newline
+func (r References) file(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading file reference: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
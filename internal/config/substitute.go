@@ -1,7 +1,6 @@
 package config
 
 import (
-	"fmt"
 	"regexp"
 )
 
@@ -38,21 +37,8 @@ func ResolveEnv(env map[string]string) map[string]string {
 	return resolved
 }
 
+// SubstituteString replaces the {{ }} expressions in input using env. See
+// Evaluator for the expressions supported.
 func SubstituteString(input string, env map[string]string) (string, error) {
-	result := re.ReplaceAllStringFunc(input, func(match string) string {
-		key := re.FindStringSubmatch(match)[1]
-
-		if val, ok := env[key]; ok {
-			return val
-		}
-
-		return match
-	})
-
-	if re.MatchString(result) {
-		return "", fmt.Errorf("unresolved variable in string: %s", input)
-	}
-
-	return result, nil
-
+	return NewEvaluator(TextVars(env), nil, "").Substitute(input)
 }
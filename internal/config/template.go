@@ -0,0 +1,504 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exprRe matches a {{ }} expression. Braces may only appear inside its
+// quoted strings, so {{"{{"}} writes a literal {{.
+var exprRe = regexp.MustCompile(`\{\{((?:[^{}"']|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')*)\}\}`)
+
+// maxDepth bounds how deeply variables whose values hold expressions of
+// their own are expanded
+const maxDepth = 10
+
+// Evaluator substitutes {{ }} expressions: variables ({{name}}), built-in
+// functions with arguments ({{random.int 1 100}}, {{base64 .token}}), time
+// arithmetic ({{now + 1h}}) and pipes ({{now | unix}}). An evaluator
+// belongs to one test: the same expression written twice in it yields the
+// same value, so a {{uuid}} in the path and the body agree.
+//
+// Variables are Text when written in the suite (env, --var, data rows) and
+// keep their JSON type when captured from a response.
+type Evaluator struct {
+	vars  map[string]interface{}
+	rand  *rand.Rand
+	now   func() time.Time
+	cache map[string]interface{}
+
+	seed  *int64
+	scope string
+}
+
+// NewEvaluator creates an evaluator over vars. Given a seed, generated
+// values depend only on the seed, scope (typically the test's name) and the
+// expression, so a seeded run repeats exactly whatever order things are
+// evaluated in. Without one they are random.
+func NewEvaluator(vars map[string]interface{}, seed *int64, scope string) *Evaluator {
+	return &Evaluator{
+		vars:  vars,
+		rand:  rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		now:   func() time.Time { return time.Now().UTC() },
+		cache: make(map[string]interface{}),
+		seed:  seed,
+		scope: scope,
+	}
+}
+
+// With returns an evaluator that also sees extra, which take precedence
+// over the variables already visible. It shares the receiver's values, so
+// an expression such as {{now | unix}} evaluates the same in both.
+func (e *Evaluator) With(extra map[string]interface{}) *Evaluator {
+	vars := make(map[string]interface{}, len(e.vars)+len(extra))
+	for k, v := range e.vars {
+		vars[k] = v
+	}
+	for k, v := range extra {
+		vars[k] = v
+	}
+	child := *e
+	child.vars = vars
+	return &child
+}
+
+// Substitute replaces every {{ }} expression in input with its value
+func (e *Evaluator) Substitute(input string) (string, error) {
+	return e.substitute(input, 0)
+}
+
+// Value evaluates a single expression, written without the braces
+func (e *Evaluator) Value(expr string) (string, error) {
+	v, err := e.value(strings.TrimSpace(expr), 0)
+	if err != nil {
+		return "", err
+	}
+	return toString(v), nil
+}
+
+// Resolve is like Substitute, except that when input is exactly one
+// expression its value keeps its type: captured numbers, booleans, objects
+// and arrays stay as they were, and Text that reads as a number, boolean or
+// null becomes one. The result is ready to be encoded as JSON.
+func (e *Evaluator) Resolve(input string) (interface{}, error) {
+	loc := exprRe.FindStringIndex(input)
+	if loc == nil || loc[0] != 0 || loc[1] != len(input) {
+		return e.Substitute(input)
+	}
+
+	v, err := e.value(strings.TrimSpace(input[2:len(input)-2]), 0)
+	if err != nil {
+		return nil, exprError(input, input, err)
+	}
+	return jsonValue(v), nil
+}
+
+// ResolveValue resolves the strings throughout a decoded YAML or JSON
+// value, descending into objects and arrays, as Resolve does. Object keys
+// are substituted as text.
+func (e *Evaluator) ResolveValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return e.Resolve(val)
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(val))
+		for k, elem := range val {
+			key, err := e.Substitute(k)
+			if err != nil {
+				return nil, err
+			}
+			if resolved[key], err = e.ResolveValue(elem); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(val))
+		for i, elem := range val {
+			var err error
+			if resolved[i], err = e.ResolveValue(elem); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	}
+	return v, nil
+}
+
+func (e *Evaluator) substitute(input string, depth int) (string, error) {
+	if !strings.Contains(input, "{{") {
+		return input, nil
+	}
+
+	var firstErr error
+	result := exprRe.ReplaceAllStringFunc(input, func(match string) string {
+		val, err := e.value(strings.TrimSpace(match[2:len(match)-2]), depth)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = exprError(input, match, err)
+			}
+			return match
+		}
+		return toString(val)
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+// exprError describes a failure to evaluate match, one of input's expressions
+func exprError(input, match string, err error) error {
+	if _, unresolved := err.(unresolvedError); unresolved {
+		return fmt.Errorf("unresolved variable in string: %s", input)
+	}
+	return fmt.Errorf("evaluating %s: %w", match, err)
+}
+
+func (e *Evaluator) value(expr string, depth int) (interface{}, error) {
+	if val, ok := e.cache[expr]; ok {
+		return val, nil
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each expression draws from its own source, so its value doesn't
+	// depend on what was evaluated before it
+	if e.seed != nil {
+		h := fnv.New64a()
+		h.Write([]byte(e.scope + "\x00" + expr))
+		outer := e.rand
+		e.rand = rand.New(rand.NewPCG(uint64(*e.seed), h.Sum64()))
+		defer func() { e.rand = outer }()
+	}
+	v, err := e.pipeline(tokens, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache[expr] = v
+	return v, nil
+}
+
+// unresolvedError reports a bare name that is neither a variable nor a function
+type unresolvedError string
+
+func (u unresolvedError) Error() string {
+	return fmt.Sprintf("unknown variable %q", string(u))
+}
+
+// token kinds
+const (
+	tokWord = iota
+	tokString
+	tokPipe
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+// tokenize splits an expression into words, quoted strings and pipes
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			tokens = append(tokens, token{kind: tokPipe, text: "|"})
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(expr) && expr[j] != c; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				}
+				b.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: b.String()})
+			i = j + 1
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t|\"'", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: expr[i:j]})
+			i = j
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return tokens, nil
+}
+
+// pipeline evaluates commands separated by "|", passing each result to the
+// next command as its last argument
+func (e *Evaluator) pipeline(tokens []token, depth int) (interface{}, error) {
+	var piped interface{}
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && tokens[i].kind != tokPipe {
+			continue
+		}
+		if i == start {
+			return nil, fmt.Errorf("empty command in pipeline")
+		}
+
+		v, err := e.command(tokens[start:i], piped, start > 0, depth)
+		if err != nil {
+			return nil, err
+		}
+		piped = v
+		start = i + 1
+	}
+	return piped, nil
+}
+
+// command evaluates a function call or operand, followed by any number of
+// "+ <operand>" or "- <operand>" terms
+func (e *Evaluator) command(tokens []token, piped interface{}, hasPiped bool, depth int) (interface{}, error) {
+	end := len(tokens)
+	for i, t := range tokens {
+		if i > 0 && t.kind == tokWord && (t.text == "+" || t.text == "-") {
+			end = i
+			break
+		}
+	}
+
+	v, err := e.call(tokens[:end], piped, hasPiped, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := tokens[end:]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("%s needs a right-hand operand", rest[0].text)
+		}
+		operand, err := e.operand(rest[1], depth)
+		if err != nil {
+			return nil, err
+		}
+		if v, err = arithmetic(v, rest[0].text, operand); err != nil {
+			return nil, err
+		}
+		rest = rest[2:]
+	}
+	return v, nil
+}
+
+// call evaluates a function with its arguments, or a single operand
+func (e *Evaluator) call(tokens []token, piped interface{}, hasPiped bool, depth int) (interface{}, error) {
+	head := tokens[0]
+	if head.kind == tokWord {
+		// Variables shadow functions, so suites with a variable named
+		// like a function keep working
+		if _, isVar := e.vars[head.text]; !isVar {
+			if fn, ok := functions[head.text]; ok {
+				args := make([]interface{}, 0, len(tokens))
+				for _, t := range tokens[1:] {
+					arg, err := e.operand(t, depth)
+					if err != nil {
+						return nil, err
+					}
+					args = append(args, arg)
+				}
+				if hasPiped {
+					args = append(args, piped)
+				}
+				v, err := fn(e, args)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", head.text, err)
+				}
+				return v, nil
+			}
+		}
+	}
+
+	if len(tokens) > 1 {
+		return nil, fmt.Errorf("unknown function %q", head.text)
+	}
+	if hasPiped {
+		return nil, fmt.Errorf("cannot pipe into %q, which is not a function", head.text)
+	}
+	return e.operand(head, depth)
+}
+
+// operand evaluates a literal or a variable reference. Variables may be
+// written bare or with a leading dot (.token).
+func (e *Evaluator) operand(t token, depth int) (interface{}, error) {
+	if t.kind == tokString {
+		return t.text, nil
+	}
+	text := t.text
+
+	// Only what starts like a number is one, so variables named nan or inf
+	// aren't taken for ParseFloat's special values
+	if startsNumeric(text) {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f, nil
+		}
+		if d, err := parseDuration(text); err == nil {
+			return d, nil
+		}
+	}
+
+	name := strings.TrimPrefix(text, ".")
+	val, ok := e.vars[name]
+	if !ok {
+		if name == text {
+			if fn, isFunc := functions[name]; isFunc {
+				return fn(e, nil)
+			}
+		}
+		// Names don't start with digits, so this was meant as a duration
+		if startsNumeric(text) {
+			return nil, fmt.Errorf("invalid duration %q (units are ns, us, ms, s, m, h and d)", text)
+		}
+		return nil, unresolvedError(name)
+	}
+
+	// A variable's value may itself contain expressions
+	if text, ok := val.(Text); ok && strings.Contains(string(text), "{{") {
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("variable %q nests expressions too deeply", name)
+		}
+		expanded, err := e.substitute(string(text), depth+1)
+		return Text(expanded), err
+	}
+	return val, nil
+}
+
+// startsNumeric reports whether text begins with a digit, optionally after
+// a sign or decimal point
+func startsNumeric(text string) bool {
+	text = strings.TrimLeft(text, "+-.")
+	return text != "" && text[0] >= '0' && text[0] <= '9'
+}
+
+// dayRe matches the days of a duration, which time.ParseDuration lacks
+var dayRe = regexp.MustCompile(`(\d*\.?\d+)d`)
+
+// parseDuration parses a Go duration, also accepting d for 24h days
+func parseDuration(text string) (time.Duration, error) {
+	return time.ParseDuration(dayRe.ReplaceAllStringFunc(text, func(days string) string {
+		n, _ := strconv.ParseFloat(strings.TrimSuffix(days, "d"), 64)
+		return strconv.FormatFloat(n*24, 'f', -1, 64) + "h"
+	}))
+}
+
+// arithmetic adds or subtracts a duration from a time, or two numbers
+func arithmetic(left interface{}, op string, right interface{}) (interface{}, error) {
+	sign := int64(1)
+	if op == "-" {
+		sign = -1
+	}
+
+	switch l := left.(type) {
+	case time.Time:
+		d, ok := right.(time.Duration)
+		if !ok {
+			return nil, fmt.Errorf("can only %s a duration to a time, got %v", opVerb(op), right)
+		}
+		return l.Add(time.Duration(sign) * d), nil
+	case int64:
+		if r, ok := right.(int64); ok {
+			return l + sign*r, nil
+		}
+	}
+
+	l, lok := toFloat(left)
+	r, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot %s %v and %v", opVerb(op), left, right)
+	}
+	return l + float64(sign)*r, nil
+}
+
+func opVerb(op string) string {
+	if op == "-" {
+		return "subtract"
+	}
+	return "add"
+}
+
+// Format renders a variable's value the way it is substituted into a string
+func Format(v interface{}) string {
+	return toString(v)
+}
+
+// toString formats a value for substitution; times use RFC 3339, and
+// objects and arrays are encoded as JSON
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case Text:
+		return string(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case time.Duration:
+		return val.String()
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonValue converts an evaluated value to the one a JSON body should carry
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case Text:
+		return val.infer()
+	case time.Time, time.Duration:
+		return toString(val)
+	}
+	return v
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	case Text:
+		f, err := strconv.ParseFloat(string(val), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubstituteOperands(t *testing.T) {
+	vars := map[string]interface{}{
+		"name":     Text("alice"),
+		"count":    Text("5"),
+		"nan":      Text("x"),
+		"inf":      Text("y"),
+		"Infinity": Text("z"),
+		"id":       int64(42),
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"{{name}}", "alice"},
+		{"{{ .name }}", "alice"},
+		{"{{nan}}", "x"},
+		{"{{inf}}", "y"},
+		{"{{Infinity}}", "z"},
+		{"{{id}}", "42"},
+		{"{{count + 2}}", "7"},
+		{"{{id - 2}}", "40"},
+		{"{{1.5}}", "1.5"},
+		{"{{-3}}", "-3"},
+		{"id={{id}}&n={{name}}", "id=42&n=alice"},
+		{`{{"{{"}}`, "{{"},
+		{`{{"{{"}}name}}`, "{{name}}"},
+		{`{{'}}'}}`, "}}"},
+		{`a {{"{{"}}#each}} b`, "a {{#each}} b"},
+	}
+
+	e := NewEvaluator(vars, nil, "t")
+	for _, tt := range tests {
+		got, err := e.Substitute(tt.in)
+		if err != nil {
+			t.Errorf("Substitute(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Substitute(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSubstituteErrors(t *testing.T) {
+	e := NewEvaluator(map[string]interface{}{}, nil, "t")
+	for _, in := range []string{"{{missing}}", "{{nan}}", "{{now - 3x}}", "{{name | nope}}"} {
+		if got, err := e.Substitute(in); err == nil {
+			t.Errorf("Substitute(%q) = %q, expected an error", in, got)
+		}
+	}
+}
+
+func TestSubstitutePipes(t *testing.T) {
+	vars := map[string]interface{}{
+		"token": Text("s3cr3t"),
+		"query": Text("a b&c"),
+		"mixed": Text("  MiXed  "),
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"{{now | unix}}", "1700000000"},
+		{"{{now | unixms}}", "1700000000000"},
+		{"{{now | rfc3339}}", "2023-11-14T22:13:20Z"},
+		{"{{now | date}}", "2023-11-14"},
+		{"{{now + 1h | rfc3339}}", "2023-11-14T23:13:20Z"},
+		{"{{now - 2d | date}}", "2023-11-12"},
+		{`{{now | format "15:04"}}`, "22:13"},
+		{"{{token | base64}}", "czNjcjN0"},
+		{"{{base64 .token}}", "czNjcjN0"},
+		{"{{query | urlencode}}", "a+b%26c"},
+		{"{{token | sha256}}", "4e738ca5563c06cfd0018299933d58db1dd8bf97f6973dc99bf6cdc64b5550bd"},
+		{"{{mixed | trim | lower}}", "mixed"},
+		{`{{"abc" | upper}}`, "ABC"},
+	}
+
+	e := NewEvaluator(vars, nil, "t")
+	e.now = func() time.Time { return time.Unix(1700000000, 0).UTC() }
+	for _, tt := range tests {
+		got, err := e.Substitute(tt.in)
+		if err != nil {
+			t.Errorf("Substitute(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Substitute(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSeededValues(t *testing.T) {
+	seed := int64(7)
+	exprs := []string{"{{uuid}}", "{{random.int 1 1000000}}", "{{random.string 16}}", "{{random.email}}"}
+
+	eval := func(e *Evaluator, in string) string {
+		t.Helper()
+		got, err := e.Substitute(in)
+		if err != nil {
+			t.Fatalf("Substitute(%q): %v", in, err)
+		}
+		return got
+	}
+
+	for _, in := range exprs {
+		a := eval(NewEvaluator(nil, &seed, "test"), in)
+
+		// Evaluating other expressions first doesn't change the value
+		e := NewEvaluator(nil, &seed, "test")
+		for _, other := range exprs {
+			if other != in {
+				eval(e, other)
+			}
+		}
+		if b := eval(e, in); a != b {
+			t.Errorf("%s with the same seed: %q, then %q", in, a, b)
+		}
+
+		if c := eval(NewEvaluator(nil, &seed, "other test"), in); a == c {
+			t.Errorf("%s in another scope repeated %q", in, a)
+		}
+	}
+
+	// The same expression evaluates once per evaluator
+	e := NewEvaluator(nil, nil, "t")
+	if a, b := eval(e, "{{uuid}}"), eval(e, "{{ uuid }}"); a != b {
+		t.Errorf("uuid changed within one evaluator: %q, then %q", a, b)
+	}
+}
+
+func TestParseDotEnv(t *testing.T) {
+	tests := []struct {
+		name, in string
+		want     map[string]string
+	}{
+		{"plain", "A=1\nB = two words ", map[string]string{"A": "1", "B": "two words"}},
+		{"comments and blanks", "# note\n\nA=1 # inline\nB=a#b", map[string]string{"A": "1", "B": "a#b"}},
+		{"export", "export TOKEN=abc", map[string]string{"TOKEN": "abc"}},
+		{"double quoted", `A="x # not a comment"`, map[string]string{"A": "x # not a comment"}},
+		{"escapes", `A="line\nnext\t\"q\" \\"`, map[string]string{"A": "line\nnext\t\"q\" \\"}},
+		{"single quoted", `A='raw\n "x"' # note`, map[string]string{"A": `raw\n "x"`}},
+		{"empty", "A=\nB=\"\"", map[string]string{"A": "", "B": ""}},
+		{"equals in value", "URL=http://x?a=1&b=2", map[string]string{"URL": "http://x?a=1&b=2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotEnv([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("ParseDotEnv: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, in := range []string{"NOVALUE", "=1", `A="open`, "A='open"} {
+		if got, err := ParseDotEnv([]byte(in)); err == nil {
+			t.Errorf("ParseDotEnv(%q) = %q, expected an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Text is a variable's value as written in a suite, on the command line or
+// in a data file. Unlike a captured value it carries no type of its own.
+type Text string
+
+// TextVars converts string variables to Text
+func TextVars(vars map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		out[k] = Text(v)
+	}
+	return out
+}
+
+// infer returns the JSON number, boolean or null that t spells, or t as a
+// string. Numbers are kept verbatim, so "1.50" stays 1.50, and ones JSON
+// would reject, such as "007", stay strings.
+func (t Text) infer() interface{} {
+	s := string(t)
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if s != "" && (s[0] == '-' || s[0] >= '0' && s[0] <= '9') && strings.TrimSpace(s) == s && json.Valid([]byte(s)) {
+		return json.Number(s)
+	}
+	return s
+}
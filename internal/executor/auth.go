@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// authorize adds the credentials of auth to req. Digest credentials are
+// only sent once the server asks for them (see digestChallenge). OAuth2
+// tokens are fetched through transport.
+func authorize(ctx context.Context, req *http.Request, vars *config.Evaluator, auth *models.Auth, transport http.RoundTripper) error {
+	if auth == nil || auth.None {
+		return nil
+	}
+
+	switch {
+	case auth.Basic != nil:
+		creds, err := substituteAll(vars, auth.Basic.Username, auth.Basic.Password)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(creds[0], creds[1])
+
+	case auth.Bearer != "":
+		token, err := vars.Substitute(auth.Bearer)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+	case auth.APIKey != nil:
+		key, err := substituteAll(vars, auth.APIKey.Header, auth.APIKey.Query, auth.APIKey.Value)
+		if err != nil {
+			return err
+		}
+		if key[0] != "" {
+			req.Header.Set(key[0], key[2])
+			break
+		}
+		param := url.Values{key[1]: {key[2]}}.Encode()
+		if req.URL.RawQuery != "" {
+			param = "&" + param
+		}
+		req.URL.RawQuery += param
+
+	case auth.OAuth2 != nil:
+		token, err := oauth2Token(ctx, vars, auth.OAuth2, transport)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// substituteAll substitutes variables in each of values
+func substituteAll(vars *config.Evaluator, values ...string) ([]string, error) {
+	resolved := make([]string, len(values))
+	for i, v := range values {
+		var err error
+		if resolved[i], err = vars.Substitute(v); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// digestChallenge returns the parameters of the Digest challenge in resp,
+// when the test uses digest auth and the server has refused it
+func digestChallenge(auth *models.Auth, resp *http.Response) (map[string]string, bool) {
+	if auth == nil || auth.Digest == nil || resp.StatusCode != http.StatusUnauthorized {
+		return nil, false
+	}
+	for _, header := range resp.Header.Values("WWW-Authenticate") {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(header), " ")
+		if strings.EqualFold(scheme, "Digest") {
+			return parseAuthParams(params), true
+		}
+	}
+	return nil, false
+}
+
+// parseAuthParams parses the comma-separated key=value and key="value"
+// parameters of a WWW-Authenticate challenge
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ,")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			return params
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				b.WriteByte(rest[i])
+			}
+			value, s = b.String(), rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[key] = value
+	}
+}
+
+// digestAuthorize answers a Digest challenge (RFC 7616) with creds
+func digestAuthorize(req *http.Request, vars *config.Evaluator, creds *models.Credentials, challenge map[string]string) error {
+	user, err := substituteAll(vars, creds.Username, creds.Password)
+	if err != nil {
+		return err
+	}
+
+	algorithm := challenge["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+	upper := strings.ToUpper(algorithm)
+	sess := strings.HasSuffix(upper, "-SESS")
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(upper, "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return fmt.Errorf("digest auth: unsupported algorithm %s", algorithm)
+	}
+	h := func(parts ...string) string {
+		sum := newHash()
+		sum.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	var qop string
+	if offered := challenge["qop"]; offered != "" {
+		for _, q := range strings.Split(offered, ",") {
+			if strings.TrimSpace(q) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return fmt.Errorf("digest auth: unsupported qop %s", offered)
+		}
+	}
+
+	realm, nonce, uri := challenge["realm"], challenge["nonce"], req.URL.RequestURI()
+	const nc = "00000001"
+	cnonce := make([]byte, 8)
+	rand.Read(cnonce)
+	cn := hex.EncodeToString(cnonce)
+
+	ha1 := h(user[0], realm, user[1])
+	if sess {
+		ha1 = h(ha1, nonce, cn)
+	}
+	ha2 := h(req.Method, uri)
+
+	var response string
+	if qop != "" {
+		response = h(ha1, nonce, nc, cn, qop, ha2)
+	} else {
+		response = h(ha1, nonce, ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf("username=%q", user[0]),
+		fmt.Sprintf("realm=%q", realm),
+		fmt.Sprintf("nonce=%q", nonce),
+		fmt.Sprintf("uri=%q", uri),
+		"algorithm=" + algorithm,
+		fmt.Sprintf("response=%q", response),
+	}
+	if qop != "" {
+		fields = append(fields, "qop="+qop, "nc="+nc, fmt.Sprintf("cnonce=%q", cn))
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		fields = append(fields, fmt.Sprintf("opaque=%q", opaque))
+	}
+	req.Header.Set("Authorization", "Digest "+strings.Join(fields, ", "))
+	return nil
+}
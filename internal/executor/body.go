@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// requestBody builds the body of req from whichever body field it sets and
+// returns it with the Content-Type it calls for. A body_file is returned as
+// the open *os.File, which the HTTP client closes once it is sent.
+func requestBody(vars *config.Evaluator, req models.Request) (io.Reader, string, error) {
+	switch {
+	case req.Body != nil:
+		resolved, err := vars.ResolveValue(req.Body)
+		if err != nil {
+			return nil, "", err
+		}
+		b, err := json.Marshal(resolved)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(b), "application/json", nil
+
+	case req.BodyRaw != "":
+		raw, err := vars.Substitute(req.BodyRaw)
+		if err != nil {
+			return nil, "", err
+		}
+		return strings.NewReader(raw), "text/plain; charset=utf-8", nil
+
+	case req.Form != nil:
+		form := make(url.Values, len(req.Form))
+		for k, v := range req.Form {
+			key, err := vars.Substitute(k)
+			if err != nil {
+				return nil, "", err
+			}
+			val, err := vars.Substitute(v)
+			if err != nil {
+				return nil, "", err
+			}
+			form.Set(key, val)
+		}
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+
+	case req.Multipart != nil:
+		return multipartBody(vars, req.Multipart)
+
+	case req.BodyFile != "":
+		path, err := vars.Substitute(req.BodyFile)
+		if err != nil {
+			return nil, "", err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("body_file: %w", err)
+		}
+		return f, contentTypeOf(path), nil
+	}
+	return nil, "", nil
+}
+
+// multipartBody encodes parts as multipart/form-data
+func multipartBody(vars *config.Evaluator, parts models.Multipart) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, part := range parts {
+		name, err := vars.Substitute(part.Name)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if part.File == "" {
+			value, err := vars.Substitute(part.Value)
+			if err != nil {
+				return nil, "", err
+			}
+			if err := w.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		if err := writeFilePart(vars, w, name, part); err != nil {
+			return nil, "", fmt.Errorf("multipart %s: %w", name, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeFilePart adds an uploaded file to a multipart body
+func writeFilePart(vars *config.Evaluator, w *multipart.Writer, name string, part models.Part) error {
+	path, err := vars.Substitute(part.File)
+	if err != nil {
+		return err
+	}
+	filename, err := vars.Substitute(part.Filename)
+	if err != nil {
+		return err
+	}
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	contentType, err := vars.Substitute(part.ContentType)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = contentTypeOf(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(name), quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	dst, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, f)
+	return err
+}
+
+// contentTypeOf guesses a file's Content-Type from its extension
+func contentTypeOf(path string) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
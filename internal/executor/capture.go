@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dawgdevv/probe/internal/assert"
+)
+
+// captureValues extracts the variables declared in a test's capture block
+// from the response. Sources are "status", "header:<Name>", "json:<path>"
+// or a bare JSON path. JSON values keep their type.
+func captureValues(rules map[string]string, resp *http.Response, body []byte) (map[string]interface{}, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	captured := make(map[string]interface{}, len(rules))
+	for name, source := range rules {
+		source = strings.TrimSpace(source)
+
+		switch {
+		case source == "status":
+			captured[name] = int64(resp.StatusCode)
+
+		case strings.HasPrefix(source, "header:"):
+			header := strings.TrimSpace(strings.TrimPrefix(source, "header:"))
+			values := resp.Header.Values(header)
+			if len(values) == 0 {
+				return nil, fmt.Errorf("capture %s: header %s not found", name, header)
+			}
+			captured[name] = values[0]
+
+		default:
+			path := strings.TrimPrefix(source, "json:")
+			val, err := assert.ExtractJSON(body, path)
+			if err != nil {
+				return nil, fmt.Errorf("capture %s: %w", name, err)
+			}
+			captured[name] = val
+		}
+	}
+
+	return captured, nil
+}
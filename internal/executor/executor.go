@@ -1,11 +1,14 @@
 package executor
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/dawgdevv/probe/internal/assert"
@@ -13,97 +16,240 @@ import (
 	"github.com/dawgdevv/probe/pkg/models"
 )
 
+// Test outcomes reported by Result.Status
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Phases of a suite a Result can belong to; tests themselves have no phase
+const (
+	PhaseSetup    = "setup"
+	PhaseTeardown = "teardown"
+)
+
 type Result struct {
 	Name       string
+	Suite      string
+	Phase      string
 	Passed     bool
 	StatusCode int
 	Error      error
 	Duration   time.Duration
+	Captures   map[string]interface{}
+
+	// Failures lists every expectation that did not hold; Error summarises them
+	Failures []assert.Failure
+
+	// Attempts records each try when the test has a retry policy
+	Attempts []Attempt
+
+	// Polls counts the requests made while waiting for an until condition
+	Polls int
+
+	// Skipped is set when the test was never run, with SkipReason explaining why
+	Skipped    bool
+	SkipReason string
 }
 
-func RunTest(baseURL string, env map[string]string, test models.TestCase) Result {
-	start := time.Now()
+// Attempt is the outcome of one try of a retried test
+type Attempt struct {
+	StatusCode int
+	Error      error
+	Duration   time.Duration
+}
 
-	resolvePath, err := config.SubstituteString(test.Request.Path, env)
+// Flaky reports whether the test passed only after being retried
+func (r Result) Flaky() bool {
+	return r.Passed && len(r.Attempts) > 1
+}
 
-	if err != nil {
-		return Result{Name: test.Name, Passed: false, Error: err}
-	}
-	url := baseURL + resolvePath
-
-	var body *bytes.Reader
-
-	if test.Request.Body != nil {
-		// Substitute env variables in body values
-		resolvedBody := make(map[string]interface{}, len(test.Request.Body))
-		for k, v := range test.Request.Body {
-			if strVal, ok := v.(string); ok {
-				resolved, err := config.SubstituteString(strVal, env)
-				if err != nil {
-					return Result{Name: test.Name, Passed: false, Error: err}
-				}
-				resolvedBody[k] = resolved
-			} else {
-				resolvedBody[k] = v
-			}
+// Status returns the outcome of the test as one of the Status constants
+func (r Result) Status() string {
+	switch {
+	case r.Skipped:
+		return StatusSkipped
+	case r.Passed:
+		return StatusPassed
+	default:
+		return StatusFailed
+	}
+}
+
+// Redact returns a copy of the result with mask applied to everything it
+// reports: its name, errors, failures and captured values
+func (r Result) Redact(mask func(string) string) Result {
+	r.Name = mask(r.Name)
+	r.Error = redactError(r.Error, mask)
+	r.SkipReason = mask(r.SkipReason)
+
+	if len(r.Failures) > 0 {
+		failures := make([]assert.Failure, len(r.Failures))
+		for i, f := range r.Failures {
+			failures[i] = f.Redact(mask)
 		}
-		b, err := json.Marshal(resolvedBody)
-		if err != nil {
-			return Result{Name: test.Name, Passed: false, Error: err}
+		r.Failures = failures
+	}
+
+	if len(r.Attempts) > 0 {
+		attempts := make([]Attempt, len(r.Attempts))
+		for i, a := range r.Attempts {
+			a.Error = redactError(a.Error, mask)
+			attempts[i] = a
 		}
-		body = bytes.NewReader(b)
+		r.Attempts = attempts
+	}
 
-	} else {
-		body = bytes.NewReader(nil)
+	if len(r.Captures) > 0 {
+		captures := make(map[string]interface{}, len(r.Captures))
+		for k, v := range r.Captures {
+			captures[k] = assert.RedactValue(v, mask)
+		}
+		r.Captures = captures
 	}
 
-	req, err := http.NewRequest(test.Request.Method, url, body)
+	return r
+}
 
-	if err != nil {
-		return Result{Name: test.Name, Passed: false, Error: err}
+// redactError masks err's message, keeping the original error when there
+// is nothing to mask
+func redactError(err error, mask func(string) string) error {
+	if err == nil {
+		return nil
 	}
+	msg := err.Error()
+	if masked := mask(msg); masked != msg {
+		return errors.New(masked)
+	}
+	return err
+}
 
-	for k, v := range test.Request.Headers {
-		val, err := config.SubstituteString(v, env)
+// Skip builds the result for a test that was not run
+func Skip(name, reason string) Result {
+	return Result{Name: name, Skipped: true, SkipReason: reason}
+}
 
-		if err != nil {
-			return Result{Name: test.Name, Passed: false, Error: err}
+// DefaultTimeout applies to requests when neither the test nor the suite sets one
+const DefaultTimeout = 10 * time.Second
+
+// Options carry the state of one run that a test's definition doesn't
+type Options struct {
+	// Seed makes the test's generated values ({{uuid}}, {{random.int 1 9}})
+	// repeatable; nil draws fresh ones each run
+	Seed *int64
+
+	// Jar holds the cookies of the test's session, nil when it has none
+	Jar http.CookieJar
+}
+
+// RunTest executes a single test, retrying it according to its retry
+// policy. Each request is cancelled when ctx is done or the test's timeout
+// elapses, whichever comes first.
+// env maps variable names to config.Text or to values captured earlier.
+func RunTest(ctx context.Context, baseURL string, env map[string]interface{}, test models.TestCase, opts Options) Result {
+	// One evaluator serves every attempt, so generated values such as
+	// {{uuid}} stay the same when the test is retried or polls
+	vars := config.NewEvaluator(withVars(env, test.Vars), opts.Seed, test.Name)
+
+	policy := test.Retry
+	if policy == nil || policy.Attempts <= 1 {
+		return runAttempt(ctx, baseURL, vars, test, opts)
+	}
+
+	var attempts []Attempt
+	for n := 1; ; n++ {
+		result := runAttempt(ctx, baseURL, vars, test, opts)
+		attempts = append(attempts, Attempt{StatusCode: result.StatusCode, Error: result.Error, Duration: result.Duration})
+
+		if result.Passed || n >= policy.Attempts || !shouldRetry(policy, result) {
+			result.Attempts = attempts
+			return result
+		}
+
+		if err := sleepContext(ctx, backoffDelay(policy, n)); err != nil {
+			result.Attempts = attempts
+			return result
 		}
-		req.Header.Set(k, val)
 	}
+}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// withVars overlays a data-driven test's row values on env
+func withVars(env map[string]interface{}, vars map[string]string) map[string]interface{} {
+	if len(vars) == 0 {
+		return env
+	}
+	merged := make(map[string]interface{}, len(env)+len(vars))
+	for k, v := range env {
+		merged[k] = v
 	}
+	for k, v := range vars {
+		merged[k] = config.Text(v)
+	}
+	return merged
+}
 
-	resp, err := client.Do(req)
+// runAttempt makes one request for the test (or a series of them when the
+// test polls with until) and checks its expectations
+func runAttempt(ctx context.Context, baseURL string, vars *config.Evaluator, test models.TestCase, opts Options) Result {
+	start := time.Now()
+
+	resp, err := send(ctx, baseURL, vars, test, opts)
 	if err != nil {
-		return Result{Name: test.Name, Passed: false, Error: fmt.Errorf("request failed: %w", err)}
+		return Result{Name: test.Name, Passed: false, Error: err, Duration: time.Since(start)}
 	}
-	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return Result{Name: test.Name, Passed: false, Error: err}
+	polls := 0
+	if test.Until != nil {
+		var unmet assert.Failures
+		resp, polls, unmet, err = poll(ctx, baseURL, vars, test, opts, resp)
+		if err != nil {
+			return Result{Name: test.Name, Passed: false, Error: err, Duration: time.Since(start), Polls: polls}
+		}
+		if len(unmet) > 0 {
+			return Result{
+				Name:       test.Name,
+				Passed:     false,
+				StatusCode: resp.StatusCode,
+				Error: fmt.Errorf("condition not met after %d polls in %v (last response: %d %s): %w",
+					polls, time.Since(start).Round(time.Millisecond), resp.StatusCode, snippet(resp.body), unmet),
+				Failures: unmet,
+				Duration: time.Since(start),
+				Polls:    polls,
+			}
+		}
+	}
+
+	bodyBytes := resp.body
+
+	// A polled test reports the time spent waiting, but max_duration still
+	// applies to the final request alone
+	elapsed := resp.elapsed
+	if polls > 0 {
+		elapsed = time.Since(start)
 	}
 
-	if resp.StatusCode != test.Expect.Status {
+	if failures := checkExpectations(test.Expect, resp.Response, bodyBytes, resp.elapsed, vars); len(failures) > 0 {
 		return Result{
 			Name:       test.Name,
 			Passed:     false,
 			StatusCode: resp.StatusCode,
-			Error:      fmt.Errorf("expected %d, got %d", test.Expect.Status, resp.StatusCode),
+			Error:      failures,
+			Failures:   failures,
+			Duration:   elapsed,
+			Polls:      polls,
 		}
 	}
 
-	if len(test.Expect.JSON) > 0 {
-		if err := assert.AssertJSON(bodyBytes, test.Expect.JSON); err != nil {
-			return Result{
-				Name:       test.Name,
-				Passed:     false,
-				StatusCode: resp.StatusCode,
-				Error:      err,
-			}
+	captures, err := captureValues(test.Capture, resp.Response, bodyBytes)
+	if err != nil {
+		return Result{
+			Name:       test.Name,
+			Passed:     false,
+			StatusCode: resp.StatusCode,
+			Error:      err,
+			Duration:   elapsed,
+			Polls:      polls,
 		}
 	}
 
@@ -111,6 +257,221 @@ func RunTest(baseURL string, env map[string]string, test models.TestCase) Result
 		Name:       test.Name,
 		Passed:     true,
 		StatusCode: resp.StatusCode,
-		Duration:   time.Since(start),
+		Duration:   elapsed,
+		Captures:   captures,
+		Polls:      polls,
+	}
+}
+
+// response is a completed HTTP exchange whose body has already been read
+type response struct {
+	*http.Response
+	body    []byte
+	elapsed time.Duration
+}
+
+// send builds the test's request from vars, sends it and reads the response
+func send(ctx context.Context, baseURL string, vars *config.Evaluator, test models.TestCase, opts Options) (*response, error) {
+	start := time.Now()
+
+	timeout := test.Timeout.Std()
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := newClient(vars, test.HTTP, opts.Jar)
+	if err != nil {
+		return nil, err
 	}
+
+	req, err := newRequest(reqCtx, baseURL, vars, test, client.Transport)
+	if err != nil {
+		// Fetching an OAuth2 token can run out of time like any request
+		if reqCtx.Err() != nil {
+			return nil, requestError(ctx, reqCtx, timeout, err)
+		}
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, reqCtx, timeout, err)
+	}
+
+	// Digest auth answers the server's challenge with a second request
+	if challenge, ok := digestChallenge(test.Auth, resp); ok {
+		_, err := io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, requestError(ctx, reqCtx, timeout, err)
+		}
+
+		if req, err = newRequest(reqCtx, baseURL, vars, test, client.Transport); err != nil {
+			return nil, err
+		}
+		if err := digestAuthorize(req, vars, test.Auth.Digest, challenge); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+		if resp, err = client.Do(req); err != nil {
+			return nil, requestError(ctx, reqCtx, timeout, err)
+		}
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError(ctx, reqCtx, timeout, err)
+	}
+
+	return &response{Response: resp, body: bodyBytes, elapsed: time.Since(start)}, nil
+}
+
+// newRequest builds the test's request: its URL, body, auth, headers and
+// cookies, in that order, so headers the test sets win over defaults.
+// OAuth2 tokens are fetched through transport.
+func newRequest(ctx context.Context, baseURL string, vars *config.Evaluator, test models.TestCase, transport http.RoundTripper) (*http.Request, error) {
+	url, err := requestURL(vars, baseURL, test.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := requestBody(vars, test.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, test.Request.Method, url, body)
+	if err != nil {
+		if f, ok := body.(*os.File); ok {
+			f.Close()
+		}
+		return nil, err
+	}
+
+	if err := prepareRequest(ctx, req, vars, test, contentType, transport); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// prepareRequest sets the headers and cookies of a newly built request and
+// signs it
+func prepareRequest(ctx context.Context, req *http.Request, vars *config.Evaluator, test models.TestCase, contentType string, transport http.RoundTripper) error {
+	// Files are streamed, so their length is only known from disk
+	if f, ok := req.Body.(*os.File); ok {
+		if info, err := f.Stat(); err == nil {
+			req.ContentLength = info.Size()
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if err := authorize(ctx, req, vars, test.Auth, transport); err != nil {
+		return err
+	}
+
+	for k, v := range test.Request.Headers {
+		val, err := vars.Substitute(v)
+
+		if err != nil {
+			return err
+		}
+		req.Header.Set(k, val)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(test.Request.Cookies)) {
+		val, err := vars.Substitute(test.Request.Cookies[name])
+		if err != nil {
+			return err
+		}
+		req.AddCookie(&http.Cookie{Name: name, Value: val})
+	}
+
+	// Signatures cover the finished request, so come last
+	return sign(req, vars, test.Auth)
+}
+
+var (
+	errSuiteDeadline  = errors.New("timed out: suite deadline exceeded")
+	errSuiteCancelled = errors.New("cancelled: suite run was cancelled")
+)
+
+// requestError explains why a request did not complete, distinguishing the
+// test's own timeout from the suite being cancelled or running out of time
+func requestError(suiteCtx, reqCtx context.Context, timeout time.Duration, err error) error {
+	switch {
+	case errors.Is(suiteCtx.Err(), context.DeadlineExceeded):
+		return errSuiteDeadline
+	case errors.Is(suiteCtx.Err(), context.Canceled):
+		return errSuiteCancelled
+	case errors.Is(reqCtx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+	return fmt.Errorf("request failed: %w", err)
+}
+
+// checkExpectations evaluates every expectation on the response and returns
+// all that failed, rather than stopping at the first
+func checkExpectations(expect models.Expect, resp *http.Response, body []byte, elapsed time.Duration, vars *config.Evaluator) assert.Failures {
+	expect, err := resolveExpect(expect, vars)
+	if err != nil {
+		return assert.Failures{{Message: err.Error()}}
+	}
+
+	var failures assert.Failures
+
+	if resp.StatusCode != expect.Status {
+		failures = append(failures, assert.Failure{
+			Target:   assert.TargetStatus,
+			Operator: "eq",
+			Expected: expect.Status,
+			Actual:   resp.StatusCode,
+			Message:  fmt.Sprintf("expected %d, got %d", expect.Status, resp.StatusCode),
+		})
+	}
+
+	if len(expect.Headers) > 0 {
+		failures = append(failures, assert.AssertHeaders(resp.Header, expect.Headers)...)
+	}
+
+	if len(expect.Cookies) > 0 {
+		failures = append(failures, assert.AssertCookies(resp.Cookies(), expect.Cookies)...)
+	}
+
+	if expect.Body != nil {
+		failures = append(failures, assert.AssertBody(body, expect.Body)...)
+	}
+
+	if len(expect.JSON) > 0 {
+		failures = append(failures, assert.AssertJSON(body, expect.JSON)...)
+	}
+
+	if expect.RedirectTo != nil {
+		failures = append(failures, assert.AssertRedirect(redirectTarget(resp), expect.RedirectTo)...)
+	}
+
+	if expect.JWT != nil {
+		failures = append(failures, checkJWT(expect.JWT, resp, body)...)
+	}
+
+	if limit := expect.MaxDuration.Std(); limit > 0 && elapsed > limit {
+		failures = append(failures, assert.Failure{
+			Target:   assert.TargetDuration,
+			Operator: "lte",
+			Expected: limit.String(),
+			Actual:   elapsed.Round(time.Millisecond).String(),
+			Message:  fmt.Sprintf("took %v, expected at most %v", elapsed.Round(time.Millisecond), limit),
+		})
+	}
+
+	return failures
 }
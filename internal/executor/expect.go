@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// resolveExpect substitutes variables into every expected value, so values
+// captured by earlier tests can be asserted like any other
+func resolveExpect(expect models.Expect, vars *config.Evaluator) (models.Expect, error) {
+	var err error
+	if expect.Headers, err = resolveRules(vars, expect.Headers); err != nil {
+		return expect, fmt.Errorf("headers: %w", err)
+	}
+	if expect.Cookies, err = resolveRules(vars, expect.Cookies); err != nil {
+		return expect, fmt.Errorf("cookies: %w", err)
+	}
+	if expect.JSON, err = resolveRules(vars, expect.JSON); err != nil {
+		return expect, fmt.Errorf("json: %w", err)
+	}
+	if expect.RedirectTo, err = vars.ResolveValue(expect.RedirectTo); err != nil {
+		return expect, fmt.Errorf("redirect_to: %w", err)
+	}
+
+	if expect.Body != nil {
+		body := *expect.Body
+		if body.Exact != nil {
+			exact, err := vars.Substitute(*body.Exact)
+			if err != nil {
+				return expect, fmt.Errorf("body: %w", err)
+			}
+			body.Exact = &exact
+		}
+		if body.Regex, err = vars.Substitute(body.Regex); err != nil {
+			return expect, fmt.Errorf("body: %w", err)
+		}
+		if body.Contains, err = substituteList(vars, body.Contains); err != nil {
+			return expect, fmt.Errorf("body: %w", err)
+		}
+		if body.NotContains, err = substituteList(vars, body.NotContains); err != nil {
+			return expect, fmt.Errorf("body: %w", err)
+		}
+		expect.Body = &body
+	}
+
+	if expect.JWT != nil {
+		jwt := *expect.JWT
+		fields, err := substituteAll(vars, jwt.From, jwt.Algorithm, jwt.Key)
+		if err != nil {
+			return expect, fmt.Errorf("jwt: %w", err)
+		}
+		jwt.From, jwt.Algorithm, jwt.Key = fields[0], fields[1], fields[2]
+		if jwt.Claims, err = resolveRules(vars, jwt.Claims); err != nil {
+			return expect, fmt.Errorf("jwt: %w", err)
+		}
+		expect.JWT = &jwt
+	}
+	return expect, nil
+}
+
+// resolveRules substitutes variables into the names and expected values of
+// rules
+func resolveRules(vars *config.Evaluator, rules map[string]interface{}) (map[string]interface{}, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	resolved, err := vars.ResolveValue(rules)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+// substituteList substitutes variables in each of values, keeping an unset
+// list unset
+func substituteList(vars *config.Evaluator, values []string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	return substituteAll(vars, values...)
+}
@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// transports caches a transport for each distinct set of options, so tests
+// that share options share connections
+var transports = struct {
+	sync.Mutex
+	entries map[transportOptions]*http.Transport
+}{entries: make(map[transportOptions]*http.Transport)}
+
+// transportOptions are the resolved options that shape a transport
+type transportOptions struct {
+	proxy      string
+	insecure   bool
+	caCert     string
+	clientCert string
+	clientKey  string
+	serverName string
+}
+
+// newClient returns a client configured by opts that keeps cookies in jar
+func newClient(vars *config.Evaluator, opts *models.HTTPOptions, jar http.CookieJar) (*http.Client, error) {
+	client := &http.Client{Jar: jar}
+	if opts == nil {
+		return client, nil
+	}
+
+	fields, err := substituteAll(vars, opts.Proxy, opts.CACert, opts.ClientCert, opts.ClientKey, opts.ServerName)
+	if err != nil {
+		return nil, err
+	}
+	resolved := transportOptions{
+		proxy:      fields[0],
+		insecure:   opts.InsecureSkipVerify != nil && *opts.InsecureSkipVerify,
+		caCert:     fields[1],
+		clientCert: fields[2],
+		clientKey:  fields[3],
+		serverName: fields[4],
+	}
+	if resolved != (transportOptions{}) {
+		if client.Transport, err = transportFor(resolved); err != nil {
+			return nil, err
+		}
+	}
+
+	client.CheckRedirect = redirectPolicy(opts.FollowRedirects)
+	return client, nil
+}
+
+// transportFor returns the cached transport for opts, building it first if
+// there is none
+func transportFor(opts transportOptions) (*http.Transport, error) {
+	transports.Lock()
+	defer transports.Unlock()
+	if t, ok := transports.entries[opts]; ok {
+		return t, nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	switch opts.proxy {
+	case "":
+		// HTTP_PROXY, HTTPS_PROXY and NO_PROXY, as by default
+	case models.NoProxy:
+		t.Proxy = nil
+	default:
+		u, err := url.Parse(opts.proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.proxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	tlsConfig, err := tlsConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	t.TLSClientConfig = tlsConfig
+
+	transports.entries[opts] = t
+	return t, nil
+}
+
+// tlsConfig builds the TLS settings of a transport
+func tlsConfig(opts transportOptions) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: opts.insecure, ServerName: opts.serverName}
+
+	if opts.caCert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		data, err := os.ReadFile(opts.caCert)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_cert: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca_cert %s: no certificates found", opts.caCert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.clientCert != "" || opts.clientKey != "" {
+		// Without a key file, the key is expected alongside the certificate
+		keyFile := opts.clientKey
+		if keyFile == "" {
+			keyFile = opts.clientCert
+		}
+		cert, err := tls.LoadX509KeyPair(opts.clientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// redirectPolicy returns the client's CheckRedirect for r. A response past
+// the last redirect to follow is returned as it is, rather than failing, so
+// it can be asserted on.
+func redirectPolicy(r *models.Redirects) func(*http.Request, []*http.Request) error {
+	switch {
+	case r == nil || (r.Follow && r.Max == 0):
+		return nil
+	case !r.Follow:
+		return func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	}
+	return func(_ *http.Request, via []*http.Request) error {
+		if len(via) > r.Max {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+}
+
+// redirectTarget is the Location of a redirect that was not followed, or
+// else the URL the client was redirected to. It is nil without a redirect.
+func redirectTarget(resp *http.Response) *url.URL {
+	if resp.StatusCode/100 == 3 {
+		if loc, err := resp.Location(); err == nil {
+			return loc
+		}
+	}
+	if resp.Request != nil && resp.Request.Response != nil {
+		return resp.Request.URL
+	}
+	return nil
+}
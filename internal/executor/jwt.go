@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dawgdevv/probe/internal/assert"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// checkJWT finds the token expect describes in the response and checks it
+func checkJWT(expect *models.JWTExpect, resp *http.Response, body []byte) []assert.Failure {
+	fail := func(err error) []assert.Failure {
+		return []assert.Failure{{Target: assert.TargetJWT, Message: err.Error()}}
+	}
+
+	source := strings.TrimSpace(expect.From)
+	var token string
+	switch {
+	case source == "":
+		return fail(fmt.Errorf("from is required (a JSON path or header:<Name>)"))
+
+	case strings.HasPrefix(source, "header:"):
+		header := strings.TrimSpace(strings.TrimPrefix(source, "header:"))
+		token = resp.Header.Get(header)
+		if token == "" {
+			return fail(fmt.Errorf("header %s not found", header))
+		}
+
+	default:
+		val, err := assert.ExtractJSON(body, strings.TrimPrefix(source, "json:"))
+		if err != nil {
+			return fail(err)
+		}
+		s, ok := val.(string)
+		if !ok {
+			return fail(fmt.Errorf("%s is not a string", source))
+		}
+		token = s
+	}
+
+	return assert.AssertJWT(token, expect.Algorithm, expect.Key, expect.Claims)
+}
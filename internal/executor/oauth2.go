@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// tokens caches OAuth2 tokens by everything used to fetch them, so each is
+// fetched once and again only when it expires, however many tests use it
+var tokens = struct {
+	sync.Mutex
+	entries map[string]*cachedToken
+}{entries: make(map[string]*cachedToken)}
+
+type cachedToken struct {
+	mu      sync.Mutex
+	value   string
+	expires time.Time // zero when the token server gave no lifetime
+}
+
+// expiryMargin is how long before its expiry a token is replaced, so it
+// doesn't lapse while a request is in flight
+const expiryMargin = 10 * time.Second
+
+// oauth2Token returns a token for cfg, fetching one when none is cached or
+// the cached one is about to expire
+func oauth2Token(ctx context.Context, vars *config.Evaluator, cfg *models.OAuth2, transport http.RoundTripper) (string, error) {
+	fields, err := substituteAll(vars, cfg.TokenURL, cfg.ClientID, cfg.ClientSecret,
+		cfg.Username, cfg.Password, strings.Join(cfg.Scopes, " "))
+	if err != nil {
+		return "", err
+	}
+	resolved := models.OAuth2{
+		TokenURL:     fields[0],
+		ClientID:     fields[1],
+		ClientSecret: fields[2],
+		Username:     fields[3],
+		Password:     fields[4],
+		Scopes:       strings.Fields(fields[5]),
+		ClientAuth:   cfg.ClientAuth,
+	}
+
+	key := strings.Join(append(fields, cfg.ClientAuth), "\x00")
+	tokens.Lock()
+	entry, ok := tokens.entries[key]
+	if !ok {
+		entry = &cachedToken{}
+		tokens.entries[key] = entry
+	}
+	tokens.Unlock()
+
+	// Tests waiting on the same token wait for one fetch
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.value != "" && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		return entry.value, nil
+	}
+
+	value, lifetime, err := fetchToken(ctx, resolved, transport)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token: %w", err)
+	}
+	entry.value, entry.expires = value, time.Time{}
+	if lifetime > 0 {
+		entry.expires = time.Now().Add(lifetime - min(expiryMargin, lifetime/2))
+	}
+	return value, nil
+}
+
+// fetchToken requests a token from the token URL through transport,
+// returning it with its lifetime
+func fetchToken(ctx context.Context, cfg models.OAuth2, transport http.RoundTripper) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if cfg.Username != "" {
+		form = url.Values{"grant_type": {"password"}, "username": {cfg.Username}, "password": {cfg.Password}}
+	}
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	if cfg.ClientAuth == "body" {
+		form.Set("client_id", cfg.ClientID)
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.ClientAuth != "body" && cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", 0, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, snippet(body))
+	}
+
+	var token struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token: %s", snippet(body))
+	}
+
+	// Some servers send expires_in as a string
+	seconds, _ := strconv.ParseFloat(strings.Trim(string(token.ExpiresIn), `"`), 64)
+	return token.AccessToken, time.Duration(seconds * float64(time.Second)), nil
+}
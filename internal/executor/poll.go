@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dawgdevv/probe/internal/assert"
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultPollMaxWait  = 30 * time.Second
+)
+
+// poll re-sends the test's request until the response meets the until
+// conditions or max_wait passes. It returns the last response, the number of
+// requests made and, if the wait expired, the conditions that were still unmet.
+func poll(ctx context.Context, baseURL string, vars *config.Evaluator, test models.TestCase, opts Options, first *response) (*response, int, assert.Failures, error) {
+	until := test.Until
+	interval := until.Interval.Std()
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	maxWait := until.MaxWait.Std()
+	if maxWait <= 0 {
+		maxWait = defaultPollMaxWait
+	}
+	deadline := time.Now().Add(maxWait)
+
+	resp := first
+	polls := 1
+	for {
+		unmet := checkUntil(until, resp, vars)
+		if len(unmet) == 0 {
+			return resp, polls, nil, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return resp, polls, unmet, nil
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return resp, polls, nil, requestError(ctx, ctx, 0, err)
+		}
+
+		next, err := send(ctx, baseURL, vars, test, opts)
+		if err != nil {
+			return resp, polls, nil, err
+		}
+		resp = next
+		polls++
+	}
+}
+
+// checkUntil evaluates the until conditions against a response
+func checkUntil(until *models.Until, resp *response, vars *config.Evaluator) assert.Failures {
+	expect := until.Expect
+	if expect.Status == 0 {
+		expect.Status = resp.StatusCode
+	}
+	return checkExpectations(expect, resp.Response, resp.body, resp.elapsed, vars)
+}
+
+// snippet shortens a response body for error messages
+func snippet(body []byte) string {
+	return assert.Truncate(strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// shouldRetry decides whether a failed attempt is worth repeating
+func shouldRetry(policy *models.RetryPolicy, result Result) bool {
+	if ctxFailure(result) {
+		return false
+	}
+	if len(policy.OnStatus) == 0 && !policy.OnError {
+		return true
+	}
+
+	// A status code of zero means no response was received
+	if result.StatusCode == 0 {
+		return policy.OnError
+	}
+	for _, code := range policy.OnStatus {
+		if result.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ctxFailure reports whether the attempt was cut short by the suite being
+// cancelled or running out of time, which no retry can fix
+func ctxFailure(result Result) bool {
+	return errors.Is(result.Error, errSuiteDeadline) || errors.Is(result.Error, errSuiteCancelled)
+}
+
+// backoffDelay returns the wait before retry n (1-based), doubling each time
+func backoffDelay(policy *models.RetryPolicy, n int) time.Duration {
+	delay := policy.Backoff.Std()
+	for i := 1; i < n; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+// sleepContext waits for d, returning early with an error if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
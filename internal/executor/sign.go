@@ -0,0 +1,298 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// signer signs a finished request, given the payload it will send
+type signer func(req *http.Request, payload []byte, vars *config.Evaluator) error
+
+// signerFor returns the signer auth configures, or nil when it has none
+func signerFor(auth *models.Auth) signer {
+	switch {
+	case auth == nil:
+		return nil
+	case auth.HMAC != nil:
+		return hmacSigner(auth.HMAC)
+	case auth.AWSSigV4 != nil:
+		return sigV4Signer(auth.AWSSigV4)
+	}
+	return nil
+}
+
+// sign applies the test's signer, if it has one, as the last step in
+// building a request
+func sign(req *http.Request, vars *config.Evaluator, auth *models.Auth) error {
+	signer := signerFor(auth)
+	if signer == nil {
+		return nil
+	}
+	payload, err := requestPayload(req)
+	if err != nil {
+		return err
+	}
+	return signer(req, payload, vars)
+}
+
+// requestPayload returns the body req will send. A streamed file is read
+// into memory, since its signature must cover it before it is sent.
+func requestPayload(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	payload, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(payload)), nil
+	}
+	return payload, nil
+}
+
+// defaultStringToSign is what HMAC signs when the suite doesn't say
+const defaultStringToSign = "{{request.method}}\n{{request.uri}}\n{{request.body_sha256}}"
+
+// hmacSigner signs the request's string to sign with a keyed hash
+func hmacSigner(cfg *models.HMAC) signer {
+	newHash := sha256.New
+	switch cfg.Algorithm {
+	case "sha1":
+		newHash = sha1.New
+	case "sha512":
+		newHash = sha512.New
+	}
+
+	return func(req *http.Request, payload []byte, vars *config.Evaluator) error {
+		key, err := vars.Substitute(cfg.Key)
+		if err != nil {
+			return err
+		}
+
+		template := cfg.StringToSign
+		if template == "" {
+			template = defaultStringToSign
+		}
+		reqVars := requestVars(req, payload)
+		toSign, err := vars.With(reqVars).Substitute(template)
+		if err != nil {
+			return fmt.Errorf("hmac string_to_sign: %w", err)
+		}
+
+		mac := hmac.New(newHash, []byte(key))
+		mac.Write([]byte(toSign))
+		signature := hex.EncodeToString(mac.Sum(nil))
+		if cfg.Encoding == "base64" {
+			signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		}
+
+		header, value := cfg.Header, cfg.Value
+		if header == "" {
+			header = "Authorization"
+		}
+		if value == "" {
+			value = "{{signature}}"
+		}
+		reqVars["signature"] = signature
+		value, err = vars.With(reqVars).Substitute(value)
+		if err != nil {
+			return fmt.Errorf("hmac value: %w", err)
+		}
+		req.Header.Set(header, value)
+		return nil
+	}
+}
+
+// requestVars exposes the parts of a request to signing templates
+func requestVars(req *http.Request, payload []byte) map[string]interface{} {
+	vars := map[string]interface{}{
+		"request.method":      req.Method,
+		"request.url":         req.URL.String(),
+		"request.host":        req.URL.Host,
+		"request.path":        req.URL.EscapedPath(),
+		"request.query":       req.URL.RawQuery,
+		"request.uri":         req.URL.RequestURI(),
+		"request.body":        string(payload),
+		"request.body_sha256": sha256Hex(payload),
+	}
+	for name, values := range req.Header {
+		vars["request.header."+name] = strings.Join(values, ",")
+	}
+	return vars
+}
+
+// signingTime is the clock SigV4 signatures are dated by
+var signingTime = time.Now
+
+// sigV4Signer signs requests with AWS Signature Version 4
+func sigV4Signer(cfg *models.AWSSigV4) signer {
+	return func(req *http.Request, payload []byte, vars *config.Evaluator) error {
+		fields, err := substituteAll(vars, cfg.AccessKey, cfg.SecretKey, cfg.SessionToken, cfg.Region, cfg.Service)
+		if err != nil {
+			return err
+		}
+		accessKey, secretKey, sessionToken, region, service := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+		now := signingTime().UTC()
+		amzDate := now.Format("20060102T150405Z")
+		date := now.Format("20060102")
+		payloadHash := sha256Hex(payload)
+
+		req.Header.Set("X-Amz-Date", amzDate)
+		if service == "s3" {
+			req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+		}
+		if sessionToken != "" {
+			req.Header.Set("X-Amz-Security-Token", sessionToken)
+		}
+
+		headers, signedHeaders := canonicalHeaders(req)
+		canonicalRequest := strings.Join([]string{
+			req.Method,
+			canonicalURI(req.URL, service != "s3"),
+			canonicalQuery(req.URL),
+			headers,
+			signedHeaders,
+			payloadHash,
+		}, "\n")
+
+		scope := date + "/" + region + "/" + service + "/aws4_request"
+		stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+		key := []byte("AWS4" + secretKey)
+		for _, part := range []string{date, region, service, "aws4_request"} {
+			key = hmacSHA256(key, part)
+		}
+		signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+		req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			accessKey, scope, signedHeaders, signature))
+		return nil
+	}
+}
+
+// unsignedHeaders may be changed on the way to the server, so are left
+// out of the signature
+var unsignedHeaders = map[string]bool{"authorization": true, "user-agent": true, "x-amzn-trace-id": true, "expect": true}
+
+// canonicalHeaders returns the canonical header block of a SigV4 request
+// and the list of headers it signs
+func canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.URL.Host}
+	if req.Host != "" {
+		values["host"] = req.Host
+	}
+	for name, vals := range req.Header {
+		lower := strings.ToLower(name)
+		if unsignedHeaders[lower] {
+			continue
+		}
+		trimmed := make([]string, len(vals))
+		for i, v := range vals {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[lower] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+// canonicalURI encodes each segment of the path, twice for every service
+// but S3
+func canonicalURI(u *url.URL, twice bool) string {
+	path := u.Path
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		seg = awsEscape(seg)
+		if twice {
+			seg = awsEscape(seg)
+		}
+		segments[i] = seg
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery sorts and encodes the query parameters
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		vals := append([]string(nil), query[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			pairs = append(pairs, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything but the unreserved characters
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// requestURL builds the URL of req: its path appended to baseURL (or used
+// as is when absolute), with its query parameters encoded onto the end
+func requestURL(vars *config.Evaluator, baseURL string, req models.Request) (string, error) {
+	path, err := vars.Substitute(req.Path)
+	if err != nil {
+		return "", err
+	}
+
+	target := path
+	if !isAbsoluteURL(path) {
+		target = baseURL + path
+	}
+	if len(req.Query) == 0 {
+		return target, nil
+	}
+
+	query := make(url.Values, len(req.Query))
+	for k, values := range req.Query {
+		key, err := vars.Substitute(k)
+		if err != nil {
+			return "", err
+		}
+		for _, v := range values {
+			val, err := vars.Substitute(v)
+			if err != nil {
+				return "", err
+			}
+			query.Add(key, val)
+		}
+	}
+
+	// Keep any fragment at the end, after the query
+	target, fragment, hasFragment := strings.Cut(target, "#")
+	sep := "?"
+	switch {
+	case strings.HasSuffix(target, "?") || strings.HasSuffix(target, "&"):
+		sep = ""
+	case strings.Contains(target, "?"):
+		sep = "&"
+	}
+	target += sep + query.Encode()
+	if hasFragment {
+		target += "#" + fragment
+	}
+	return target, nil
+}
+
+// isAbsoluteURL reports whether path names a URL of its own rather than a
+// path under the base URL
+func isAbsoluteURL(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/dawgdevv/probe/internal/executor"
 )
@@ -14,17 +15,54 @@ func NewConsoleFormatter() *ConsoleFormatter {
 	return &ConsoleFormatter{}
 }
 
-// FormatResult formats a single test result for console output
+// FormatResult formats a single test result for console output. Setup and
+// teardown steps are prefixed with their phase, and results of a named
+// suite with the suite's name.
 func (f *ConsoleFormatter) FormatResult(result executor.Result) string {
+	if result.Phase != "" {
+		result.Name = fmt.Sprintf("[%s] %s", result.Phase, result.Name)
+	}
+	if result.Suite != "" {
+		result.Name = fmt.Sprintf("%s › %s", result.Suite, result.Name)
+	}
+
+	if result.Skipped {
+		return fmt.Sprintf("↷ %s (skipped: %s)", result.Name, result.SkipReason)
+	}
+	if result.Flaky() {
+		return fmt.Sprintf("✔ %s (%d) [%v] flaky (passed on attempt %d)", result.Name, result.StatusCode, result.Duration, len(result.Attempts))
+	}
+	if result.Passed && result.Polls > 1 {
+		return fmt.Sprintf("✔ %s (%d) [%v] after %d polls", result.Name, result.StatusCode, result.Duration, result.Polls)
+	}
 	if result.Passed {
 		return fmt.Sprintf("✔ %s (%d) [%v]", result.Name, result.StatusCode, result.Duration)
 	}
+	if len(result.Attempts) > 1 {
+		return fmt.Sprintf("✖ %s (%v) after %d attempts", result.Name, result.Error, len(result.Attempts))
+	}
+	if len(result.Failures) > 1 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "✖ %s (%d assertions failed)", result.Name, len(result.Failures))
+		for _, failure := range result.Failures {
+			fmt.Fprintf(&b, "\n    • %s", failure.Error())
+		}
+		return b.String()
+	}
 	return fmt.Sprintf("✖ %s (%v)", result.Name, result.Error)
 }
 
-// FormatSummary formats the test suite summary
-func (f *ConsoleFormatter) FormatSummary(total, failed int) string {
-	return fmt.Sprintf("\n%d tests , %d failed\n", total, failed)
+// FormatSummary formats the test suite summary. total counts the tests that
+// were selected to run; notSelected those left out by tag or name filters.
+func (f *ConsoleFormatter) FormatSummary(total, failed, skipped, notSelected int) string {
+	summary := fmt.Sprintf("\n%d tests , %d failed", total, failed)
+	if skipped > 0 {
+		summary += fmt.Sprintf(" , %d skipped", skipped)
+	}
+	if notSelected > 0 {
+		summary += fmt.Sprintf(" , %d not selected", notSelected)
+	}
+	return summary + "\n"
 }
 
 // PrintResult prints a single result to stdout
@@ -33,6 +71,6 @@ func (f *ConsoleFormatter) PrintResult(result executor.Result) {
 }
 
 // PrintSummary prints the summary to stdout
-func (f *ConsoleFormatter) PrintSummary(total, failed int) {
-	fmt.Print(f.FormatSummary(total, failed))
+func (f *ConsoleFormatter) PrintSummary(total, failed, skipped, notSelected int) {
+	fmt.Print(f.FormatSummary(total, failed, skipped, notSelected))
 }
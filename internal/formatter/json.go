@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/dawgdevv/probe/internal/assert"
 	"github.com/dawgdevv/probe/internal/executor"
 )
 
@@ -17,8 +18,23 @@ func NewJSONFormatter() *JSONFormatter {
 
 // TestResultJSON represents a test result in JSON format
 type TestResultJSON struct {
-	Name       string `json:"name"`
-	Passed     bool   `json:"passed"`
+	Suite      string           `json:"suite,omitempty"`
+	Name       string           `json:"name"`
+	Phase      string           `json:"phase,omitempty"`
+	Status     string           `json:"status"`
+	Passed     bool             `json:"passed"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Failures   []assert.Failure `json:"failures,omitempty"`
+	SkipReason string           `json:"skip_reason,omitempty"`
+	Duration   string           `json:"duration"`
+	Flaky      bool             `json:"flaky,omitempty"`
+	Attempts   []AttemptJSON    `json:"attempts,omitempty"`
+	Polls      int              `json:"polls,omitempty"`
+}
+
+// AttemptJSON represents one try of a retried test
+type AttemptJSON struct {
 	StatusCode int    `json:"status_code,omitempty"`
 	Error      string `json:"error,omitempty"`
 	Duration   string `json:"duration"`
@@ -26,48 +42,82 @@ type TestResultJSON struct {
 
 // SuiteResultJSON represents the complete suite results
 type SuiteResultJSON struct {
-	TotalTests  int              `json:"total_tests"`
-	PassedTests int              `json:"passed_tests"`
-	FailedTests int              `json:"failed_tests"`
-	Results     []TestResultJSON `json:"results"`
-	Timestamp   time.Time        `json:"timestamp"`
+	TotalTests   int              `json:"total_tests"`
+	PassedTests  int              `json:"passed_tests"`
+	FailedTests  int              `json:"failed_tests"`
+	SkippedTests int              `json:"skipped_tests"`
+	NotSelected  int              `json:"not_selected,omitempty"`
+	Results      []TestResultJSON `json:"results"`
+	Timestamp    time.Time        `json:"timestamp"`
 }
 
-// Format converts results to JSON structure
-func (f *JSONFormatter) Format(results []executor.Result) SuiteResultJSON {
+// Format converts results to JSON structure. notSelected counts the tests
+// left out by tag or name filters.
+func (f *JSONFormatter) Format(results []executor.Result, notSelected int) SuiteResultJSON {
 	passed := 0
 	failed := 0
+	skipped := 0
 	jsonResults := make([]TestResultJSON, len(results))
 
 	for i, result := range results {
 		errorMsg := ""
 		if result.Error != nil {
 			errorMsg = result.Error.Error()
-			failed++
-		} else {
+		}
+
+		switch result.Status() {
+		case executor.StatusPassed:
 			passed++
+		case executor.StatusSkipped:
+			skipped++
+		default:
+			failed++
 		}
 
 		jsonResults[i] = TestResultJSON{
+			Suite:      result.Suite,
 			Name:       result.Name,
+			Phase:      result.Phase,
+			Status:     result.Status(),
 			Passed:     result.Passed,
 			StatusCode: result.StatusCode,
 			Error:      errorMsg,
+			Failures:   result.Failures,
+			SkipReason: result.SkipReason,
 			Duration:   result.Duration.String(),
+			Flaky:      result.Flaky(),
+			Attempts:   formatAttempts(result.Attempts),
+			Polls:      result.Polls,
 		}
 	}
 
 	return SuiteResultJSON{
-		TotalTests:  len(results),
-		PassedTests: passed,
-		FailedTests: failed,
-		Results:     jsonResults,
-		Timestamp:   time.Now(),
+		TotalTests:   len(results),
+		PassedTests:  passed,
+		FailedTests:  failed,
+		SkippedTests: skipped,
+		NotSelected:  notSelected,
+		Results:      jsonResults,
+		Timestamp:    time.Now(),
+	}
+}
+
+func formatAttempts(attempts []executor.Attempt) []AttemptJSON {
+	if len(attempts) == 0 {
+		return nil
+	}
+	out := make([]AttemptJSON, len(attempts))
+	for i, a := range attempts {
+		out[i] = AttemptJSON{StatusCode: a.StatusCode, Duration: a.Duration.String()}
+		if a.Error != nil {
+			out[i].Error = a.Error.Error()
+		}
 	}
+	return out
 }
 
 // Marshal converts results to JSON bytes
-func (f *JSONFormatter) Marshal(results []executor.Result) ([]byte, error) {
-	formatted := f.Format(results)
+func (f *JSONFormatter) Marshal(results []executor.Result, notSelected int) ([]byte, error) {
+	formatted := f.Format(results, notSelected)
 	return json.MarshalIndent(formatted, "", "  ")
 }
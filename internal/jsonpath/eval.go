@@ -0,0 +1,256 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Evaluate applies the path to a decoded JSON document. Paths made only of
+// field names and indexes return the single value they point to; paths with
+// wildcards, slices, unions, filters or recursive descent return a list of
+// every match.
+func (p *Path) Evaluate(data interface{}) (interface{}, error) {
+	return p.evaluate(data, data)
+}
+
+// Definite reports whether the path can select at most one value
+func (p *Path) Definite() bool {
+	for _, seg := range p.segments {
+		switch seg.kind {
+		case segWildcard, segSlice, segUnion, segFilter, segRecursive:
+			return false
+		}
+	}
+	return true
+}
+
+// EvaluateJSON parses body and evaluates the path against it
+func (p *Path) EvaluateJSON(body []byte) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("invalid json response")
+	}
+	return p.Evaluate(data)
+}
+
+func (p *Path) evaluate(data, root interface{}) (interface{}, error) {
+	definite := p.Definite()
+	nodes := []interface{}{data}
+
+	for i := 0; i < len(p.segments); i++ {
+		seg := p.segments[i]
+
+		if seg.kind == segRecursive {
+			i++
+			nodes = descendants(nodes)
+			seg = p.segments[i]
+		}
+
+		var next []interface{}
+		for _, node := range nodes {
+			matched, err := apply(seg, node, root)
+			if err != nil {
+				if definite {
+					return nil, p.errorAt(i, err)
+				}
+				continue
+			}
+			next = append(next, matched...)
+		}
+		nodes = next
+	}
+
+	if definite {
+		return nodes[0], nil
+	}
+	if nodes == nil {
+		nodes = []interface{}{}
+	}
+	return nodes, nil
+}
+
+// errorAt wraps err with the path and the segment that failed
+func (p *Path) errorAt(i int, err error) error {
+	return fmt.Errorf("path %s: %w at %s", p.raw, err, p.segments[i].text)
+}
+
+// apply runs a single segment against one node
+func apply(seg segment, node, root interface{}) ([]interface{}, error) {
+	switch seg.kind {
+	case segField:
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			// Legacy "$.length" on an array
+			if arr, isArr := node.([]interface{}); isArr && seg.name == "length" {
+				return []interface{}{len(arr)}, nil
+			}
+			return nil, fmt.Errorf("cannot read field %q of %s", seg.name, typeName(node))
+		}
+		val, exists := obj[seg.name]
+		if !exists {
+			return nil, fmt.Errorf("field %q not found", seg.name)
+		}
+		return []interface{}{val}, nil
+
+	case segIndex:
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot index %s", typeName(node))
+		}
+		idx := seg.index
+		if idx < 0 {
+			idx += len(arr)
+		}
+		if idx < 0 || idx >= len(arr) {
+			return nil, fmt.Errorf("index %d out of range (length %d)", seg.index, len(arr))
+		}
+		return []interface{}{arr[idx]}, nil
+
+	case segWildcard:
+		return children(node), nil
+
+	case segSlice:
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot slice %s", typeName(node))
+		}
+		return sliceOf(arr, seg.slice), nil
+
+	case segUnion:
+		var out []interface{}
+		for _, sub := range seg.union {
+			matched, err := apply(sub, node, root)
+			if err != nil {
+				continue
+			}
+			out = append(out, matched...)
+		}
+		return out, nil
+
+	case segFilter:
+		var out []interface{}
+		for _, child := range children(node) {
+			if seg.filter.match(child, root) {
+				out = append(out, child)
+			}
+		}
+		return out, nil
+
+	case segLength:
+		switch v := node.(type) {
+		case []interface{}:
+			return []interface{}{len(v)}, nil
+		case map[string]interface{}:
+			return []interface{}{len(v)}, nil
+		case string:
+			return []interface{}{len([]rune(v))}, nil
+		}
+		return nil, fmt.Errorf("length() applied to %s", typeName(node))
+	}
+
+	return nil, fmt.Errorf("unsupported segment")
+}
+
+// children returns the elements of an array or the values of an object,
+// the latter in key order so results are stable
+func children(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]interface{}, 0, len(v))
+		for _, k := range keys {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// descendants returns every node and all nodes nested beneath it
+func descendants(nodes []interface{}) []interface{} {
+	var out []interface{}
+	var walk func(n interface{})
+	walk = func(n interface{}) {
+		out = append(out, n)
+		for _, c := range children(n) {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return out
+}
+
+func sliceOf(arr []interface{}, bounds [3]*int) []interface{} {
+	n := len(arr)
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	if step == 0 {
+		return nil
+	}
+
+	norm := func(b *int, def int) int {
+		if b == nil {
+			return def
+		}
+		v := *b
+		if v < 0 {
+			v += n
+		}
+		if v < 0 {
+			v = -1
+			if step > 0 {
+				v = 0
+			}
+		}
+		if v > n {
+			v = n
+			if step < 0 {
+				v = n - 1
+			}
+		}
+		return v
+	}
+
+	var out []interface{}
+	if step > 0 {
+		start, end := norm(bounds[0], 0), norm(bounds[1], n)
+		for i := start; i < end; i += step {
+			out = append(out, arr[i])
+		}
+	} else {
+		start, end := norm(bounds[0], n-1), norm(bounds[1], -1)
+		for i := start; i > end && i < n; i += step {
+			out = append(out, arr[i])
+		}
+	}
+	return out
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, int, int64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
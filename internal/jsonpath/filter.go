@@ -0,0 +1,270 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// filterExpr is a parsed [?(...)] predicate
+type filterExpr struct {
+	op          string // "||", "&&", "!", "exists" or a comparison operator
+	left, right *filterExpr
+	lhs, rhs    operand
+}
+
+// operand is one side of a filter comparison: a path relative to the
+// current element (@), a path from the document root ($), or a literal
+type operand struct {
+	path     *Path
+	absolute bool
+	literal  interface{}
+}
+
+var comparisonOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(expr string) (*filterExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	for _, logical := range []string{"||", "&&"} {
+		if i := indexTopLevel(expr, logical); i >= 0 {
+			left, err := parseFilter(expr[:i])
+			if err != nil {
+				return nil, err
+			}
+			right, err := parseFilter(expr[i+len(logical):])
+			if err != nil {
+				return nil, err
+			}
+			return &filterExpr{op: logical, left: left, right: right}, nil
+		}
+	}
+
+	if strings.HasPrefix(expr, "!") && !strings.HasPrefix(expr, "!=") {
+		inner, err := parseFilter(expr[1:])
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{op: "!", left: inner}, nil
+	}
+
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && indexTopLevel(expr[1:len(expr)-1], ")") < 0 {
+		return parseFilter(expr[1 : len(expr)-1])
+	}
+
+	for _, op := range comparisonOps {
+		if i := indexTopLevel(expr, op); i >= 0 {
+			lhs, err := parseOperand(expr[:i])
+			if err != nil {
+				return nil, err
+			}
+			rhs, err := parseOperand(expr[i+len(op):])
+			if err != nil {
+				return nil, err
+			}
+			return &filterExpr{op: op, lhs: lhs, rhs: rhs}, nil
+		}
+	}
+
+	// What's left should be a lone path, tested for existence. Anything
+	// after the path is an operator we don't support, such as =~ or =.
+	if op := unsupportedOp(expr); op != "" {
+		return nil, fmt.Errorf("unsupported operator %q in %q", op, expr)
+	}
+	lhs, err := parseOperand(expr)
+	if err != nil {
+		return nil, err
+	}
+	if lhs.path == nil {
+		return nil, fmt.Errorf("%q is not a path", expr)
+	}
+	return &filterExpr{op: "exists", lhs: lhs}, nil
+}
+
+// unsupportedOp returns the operator following the path that starts expr,
+// or "" when expr is only a path
+func unsupportedOp(expr string) string {
+	end := -1
+	for _, c := range []string{" ", "\t", "=", "!", "<", ">", "~"} {
+		if i := indexTopLevel(expr, c); i > 0 && (end < 0 || i < end) {
+			end = i
+		}
+	}
+	if end < 0 {
+		return ""
+	}
+	rest := strings.TrimSpace(expr[end:])
+	if op := rest[:len(rest)-len(strings.TrimLeft(rest, "=!<>~"))]; op != "" {
+		return op
+	}
+	return strings.Fields(rest)[0]
+}
+
+func parseOperand(s string) (operand, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return operand{}, fmt.Errorf("missing operand")
+	}
+
+	switch {
+	case s[0] == '@' || s[0] == '$':
+		p, err := Parse(s)
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{path: p, absolute: s[0] == '$'}, nil
+	case s[0] == '\'' || s[0] == '"':
+		str, err := unquote(s)
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{literal: str}, nil
+	case s == "true":
+		return operand{literal: true}, nil
+	case s == "false":
+		return operand{literal: false}, nil
+	case s == "null":
+		return operand{literal: nil}, nil
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return operand{}, fmt.Errorf("invalid operand %q", s)
+	}
+	return operand{literal: n}, nil
+}
+
+// indexTopLevel finds op in s outside of quotes, brackets and parentheses
+func indexTopLevel(s, op string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			if depth == 0 && strings.HasPrefix(s[i:], op) {
+				return i
+			}
+			depth++
+		case c == ']' || c == ')':
+			if depth == 0 && strings.HasPrefix(s[i:], op) {
+				return i
+			}
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], op):
+			return i
+		}
+	}
+	return -1
+}
+
+// match reports whether the element satisfies the filter
+func (f *filterExpr) match(elem, root interface{}) bool {
+	switch f.op {
+	case "||":
+		return f.left.match(elem, root) || f.right.match(elem, root)
+	case "&&":
+		return f.left.match(elem, root) && f.right.match(elem, root)
+	case "!":
+		return !f.left.match(elem, root)
+	case "exists":
+		_, ok := f.lhs.value(elem, root)
+		return ok
+	}
+
+	left, ok := f.lhs.value(elem, root)
+	if !ok {
+		return false
+	}
+	right, ok := f.rhs.value(elem, root)
+	if !ok {
+		return false
+	}
+	return compareValues(left, right, f.op)
+}
+
+func (o operand) value(elem, root interface{}) (interface{}, bool) {
+	if o.path == nil {
+		return o.literal, true
+	}
+	start := elem
+	if o.absolute {
+		start = root
+	}
+	v, err := o.path.evaluate(start, root)
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func compareValues(a, b interface{}, op string) bool {
+	if an, ok := toFloat(a); ok {
+		if bn, ok := toFloat(b); ok {
+			switch op {
+			case "==":
+				return an == bn
+			case "!=":
+				return an != bn
+			case "<":
+				return an < bn
+			case "<=":
+				return an <= bn
+			case ">":
+				return an > bn
+			case ">=":
+				return an >= bn
+			}
+		}
+	}
+
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			switch op {
+			case "==":
+				return as == bs
+			case "!=":
+				return as != bs
+			case "<":
+				return as < bs
+			case "<=":
+				return as <= bs
+			case ">":
+				return as > bs
+			case ">=":
+				return as >= bs
+			}
+		}
+	}
+
+	switch op {
+	case "==":
+		return fmt.Sprintf("%T:%v", a, a) == fmt.Sprintf("%T:%v", b, b)
+	case "!=":
+		return fmt.Sprintf("%T:%v", a, a) != fmt.Sprintf("%T:%v", b, b)
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
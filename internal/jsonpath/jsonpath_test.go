@@ -0,0 +1,153 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const doc = `{
+	"store": {
+		"name": "corner shop",
+		"books": [
+			{"title": "Sayings", "price": 8.95, "tags": ["old"], "author": {"name": "Nigel"}},
+			{"title": "Sword", "price": 12.99, "isbn": "0-553"},
+			{"title": "Moby", "price": 8.99, "isbn": "0-395", "in stock": true},
+			{"title": "Lord", "price": 22.99}
+		],
+		"bike": {"color": "red", "price": 19.95}
+	},
+	"limit": 10,
+	"a.b": "dotted"
+}`
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		// Fields and indexes
+		{"$.store.name", `"corner shop"`},
+		{"store.name", `"corner shop"`},
+		{"$['store']['bike']['color']", `"red"`},
+		{`$["a.b"]`, `"dotted"`},
+		{"$.store.books[0].title", `"Sayings"`},
+		{"$.store.books[-1].title", `"Lord"`},
+		{"$.store.books[0].author.name", `"Nigel"`},
+		{"$.store.books.length()", `4`},
+		{"$.store.books.length", `4`},
+		{"$.store.name.length()", `11`},
+
+		// Wildcards, slices and unions
+		{"$.store.bike.*", `["red",19.95]`},
+		{"$.store.books[*].title", `["Sayings","Sword","Moby","Lord"]`},
+		{"$.store.books[1:3].title", `["Sword","Moby"]`},
+		{"$.store.books[:2].title", `["Sayings","Sword"]`},
+		{"$.store.books[-2:].title", `["Moby","Lord"]`},
+		{"$.store.books[::2].title", `["Sayings","Moby"]`},
+		{"$.store.books[::-1].title", `["Lord","Moby","Sword","Sayings"]`},
+		{"$.store.books[0,2].title", `["Sayings","Moby"]`},
+		{"$.store.bike['color','price']", `["red",19.95]`},
+		{"$.store.books[0,9].title", `["Sayings"]`},
+
+		// Recursive descent
+		{"$..isbn", `["0-553","0-395"]`},
+		{"$..books[0].title", `["Sayings"]`},
+		{"$..nothing", `[]`},
+
+		// Filters
+		{"$.store.books[?(@.price < 9)].title", `["Sayings","Moby"]`},
+		{"$.store.books[?(@.price >= 12.99)].title", `["Sword","Lord"]`},
+		{"$.store.books[?(@.title == 'Moby')].price", `[8.99]`},
+		{`$.store.books[?(@.title != "Moby")].title`, `["Sayings","Sword","Lord"]`},
+		{"$.store.books[?(@.isbn)].title", `["Sword","Moby"]`},
+		{"$.store.books[?(!@.isbn)].title", `["Sayings","Lord"]`},
+		{"$.store.books[?(@['in stock'] == true)].title", `["Moby"]`},
+		{"$.store.books[?(@.price < 10 && @.isbn)].title", `["Moby"]`},
+		{"$.store.books[?(@.price > 20 || @.tags)].title", `["Sayings","Lord"]`},
+		{"$.store.books[?(@.price > $.limit)].title", `["Sword","Lord"]`},
+		{"$.store.books[?(@.author.name == 'Nigel')].title", `["Sayings"]`},
+		{"$.store.books[?(@.price == null)].title", `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := Parse(tt.path)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := p.EvaluateJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			b, _ := json.Marshal(got)
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinite(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"$.store.books[0].title", true},
+		{"$.store.books.length()", true},
+		{"$.store.books[*]", false},
+		{"$.store.books[0:1]", false},
+		{"$.store.books[0,1]", false},
+		{"$.store.books[?(@.isbn)]", false},
+		{"$..title", false},
+	}
+
+	for _, tt := range tests {
+		p, err := Parse(tt.path)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.path, err)
+		}
+		if got := p.Definite(); got != tt.want {
+			t.Errorf("Definite(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, path := range []string{
+		"$.store[",
+		"$.store.",
+		"$..",
+		"$.store[]",
+		"$.books[a]",
+		"$.books[1:2:3:4]",
+		"$.books[x:]",
+		"$.books.size()",
+		"$.books['title]",
+		"$.books[?()]",
+		"$.books[?(@.title =~ /S.*/)]",
+		"$.books[?(@.title = 'x')]",
+		"$.books[?(@.price > cheap)]",
+		"$.books[?(true)]",
+	} {
+		if _, err := Parse(path); err == nil {
+			t.Errorf("Parse(%q) succeeded, expected an error", path)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	for _, path := range []string{
+		"$.store.missing",
+		"$.store.books[9]",
+		"$.store.name[0]",
+		"$.limit.value",
+		"$.limit.length()",
+	} {
+		p, err := Parse(path)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", path, err)
+		}
+		if got, err := p.EvaluateJSON([]byte(doc)); err == nil {
+			t.Errorf("Evaluate(%q) = %v, expected an error", path, got)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segmentKind identifies a single step of a path
+type segmentKind int
+
+const (
+	segField     segmentKind = iota // .name or ['name']
+	segIndex                        // [0], [-1]
+	segWildcard                     // .* or [*]
+	segSlice                        // [start:end:step]
+	segUnion                        // [0,2] or ['a','b']
+	segFilter                       // [?(@.active==true)]
+	segRecursive                    // .. (applies the following segment at every depth)
+	segLength                       // length()
+)
+
+type segment struct {
+	kind   segmentKind
+	text   string // source text, used in error messages
+	name   string
+	index  int
+	slice  [3]*int
+	union  []segment
+	filter *filterExpr
+}
+
+// Path is a compiled JSONPath expression
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+// String returns the expression the path was compiled from
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Parse compiles a JSONPath expression. The leading "$" is optional, so the
+// dotted keys used by older suites ("address.city") remain valid paths.
+func Parse(expr string) (*Path, error) {
+	p := &parser{src: strings.TrimSpace(expr)}
+
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", expr, err)
+	}
+
+	return &Path{raw: expr, segments: p.segments}, nil
+}
+
+type parser struct {
+	src      string
+	pos      int
+	segments []segment
+}
+
+func (p *parser) parse() error {
+	switch {
+	case p.src == "":
+		return nil
+	case p.src[0] == '$' || p.src[0] == '@':
+		p.pos = 1
+	case p.src[0] != '[' && p.src[0] != '.':
+		// Bare field name at the root
+		segs, err := p.field()
+		if err != nil {
+			return err
+		}
+		p.segments = append(p.segments, segs...)
+	}
+
+	for p.pos < len(p.src) {
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], ".."):
+			start := p.pos
+			p.pos += 2
+			p.segments = append(p.segments, segment{kind: segRecursive, text: ".."})
+			if p.pos >= len(p.src) {
+				return fmt.Errorf("recursive descent at offset %d has no target", start)
+			}
+			if p.src[p.pos] == '[' {
+				seg, err := p.bracket()
+				if err != nil {
+					return err
+				}
+				p.segments = append(p.segments, seg)
+				continue
+			}
+			segs, err := p.field()
+			if err != nil {
+				return err
+			}
+			p.segments = append(p.segments, segs...)
+
+		case p.src[p.pos] == '.':
+			p.pos++
+			segs, err := p.field()
+			if err != nil {
+				return err
+			}
+			p.segments = append(p.segments, segs...)
+
+		case p.src[p.pos] == '[':
+			seg, err := p.bracket()
+			if err != nil {
+				return err
+			}
+			p.segments = append(p.segments, seg)
+
+		default:
+			return fmt.Errorf("unexpected %q at offset %d", p.src[p.pos], p.pos)
+		}
+	}
+	return nil
+}
+
+// field parses a dotted member name, wildcard or function call
+func (p *parser) field() ([]segment, error) {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(".[(", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return nil, fmt.Errorf("empty field name at offset %d", start)
+	}
+
+	if name == "*" {
+		return []segment{{kind: segWildcard, text: ".*"}}, nil
+	}
+
+	if strings.HasPrefix(p.src[p.pos:], "()") {
+		p.pos += 2
+		if name != "length" {
+			return nil, fmt.Errorf("unknown function %s()", name)
+		}
+		return []segment{{kind: segLength, text: ".length()"}}, nil
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '(' {
+		return nil, fmt.Errorf("unexpected \"(\" after %q", name)
+	}
+
+	return []segment{{kind: segField, text: "." + name, name: name}}, nil
+}
+
+// bracket parses a [...] selector
+func (p *parser) bracket() (segment, error) {
+	start := p.pos
+	end, err := matchingBracket(p.src, p.pos)
+	if err != nil {
+		return segment{}, err
+	}
+	inner := strings.TrimSpace(p.src[p.pos+1 : end])
+	p.pos = end + 1
+	text := p.src[start:p.pos]
+
+	switch {
+	case inner == "*":
+		return segment{kind: segWildcard, text: text}, nil
+
+	case strings.HasPrefix(inner, "?"):
+		expr := strings.TrimSpace(inner[1:])
+		if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+			expr = expr[1 : len(expr)-1]
+		}
+		f, err := parseFilter(expr)
+		if err != nil {
+			return segment{}, fmt.Errorf("filter %s: %w", text, err)
+		}
+		return segment{kind: segFilter, text: text, filter: f}, nil
+	}
+
+	parts := splitTopLevel(inner, ',')
+	if len(parts) > 1 {
+		seg := segment{kind: segUnion, text: text}
+		for _, part := range parts {
+			s, err := selector(strings.TrimSpace(part), text)
+			if err != nil {
+				return segment{}, err
+			}
+			seg.union = append(seg.union, s)
+		}
+		return seg, nil
+	}
+
+	return selector(inner, text)
+}
+
+// selector parses a single name, index or slice inside brackets
+func selector(s, text string) (segment, error) {
+	if s == "" {
+		return segment{}, fmt.Errorf("empty selector %s", text)
+	}
+
+	if s[0] == '\'' || s[0] == '"' {
+		name, err := unquote(s)
+		if err != nil {
+			return segment{}, fmt.Errorf("selector %s: %w", text, err)
+		}
+		return segment{kind: segField, text: text, name: name}, nil
+	}
+
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return segment{}, fmt.Errorf("invalid slice %s", text)
+		}
+		seg := segment{kind: segSlice, text: text}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return segment{}, fmt.Errorf("invalid slice bound %q in %s", part, text)
+			}
+			seg.slice[i] = &n
+		}
+		return seg, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return segment{}, fmt.Errorf("invalid index %q in %s", s, text)
+	}
+	return segment{kind: segIndex, text: text, index: n}, nil
+}
+
+// matchingBracket returns the position of the "]" closing the "[" at open,
+// skipping over quoted strings and nested brackets
+func matchingBracket(s string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed \"[\" at offset %d", open)
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes,
+// brackets and parentheses
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	last := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	body := s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,289 @@
+// Package jwt mints and verifies JSON Web Tokens signed with HMAC
+// (HS256/384/512), RSA (RS256/384/512) or ECDSA (ES256/384/512) keys.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	_ "crypto/sha256" // hashes used by the algorithms
+	_ "crypto/sha512"
+)
+
+// Token is a decoded JWT
+type Token struct {
+	Header map[string]interface{}
+	Claims map[string]interface{}
+
+	// Payload is the claims as encoded in the token
+	Payload []byte
+
+	signingInput string
+	signature    []byte
+}
+
+// Algorithm returns the token's alg header
+func (t *Token) Algorithm() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// algorithm describes how one alg value signs
+type algorithm struct {
+	family string // HS, RS or ES
+	hash   crypto.Hash
+	size   int // ECDSA coordinate size in bytes
+}
+
+var algorithms = map[string]algorithm{
+	"HS256": {"HS", crypto.SHA256, 0},
+	"HS384": {"HS", crypto.SHA384, 0},
+	"HS512": {"HS", crypto.SHA512, 0},
+	"RS256": {"RS", crypto.SHA256, 0},
+	"RS384": {"RS", crypto.SHA384, 0},
+	"RS512": {"RS", crypto.SHA512, 0},
+	"ES256": {"ES", crypto.SHA256, 32},
+	"ES384": {"ES", crypto.SHA384, 48},
+	"ES512": {"ES", crypto.SHA512, 66},
+}
+
+func lookup(alg string) (algorithm, error) {
+	a, ok := algorithms[strings.ToUpper(alg)]
+	if !ok {
+		return algorithm{}, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	return a, nil
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign mints a token. key is the HMAC secret for HS algorithms and a PEM
+// private key (PKCS #1, PKCS #8 or SEC 1) for RS and ES ones. header adds
+// fields such as kid to the token's header.
+func Sign(alg, key string, header, claims map[string]interface{}) (string, error) {
+	a, err := lookup(alg)
+	if err != nil {
+		return "", err
+	}
+
+	h := map[string]interface{}{"typ": "JWT"}
+	for k, v := range header {
+		h[k] = v
+	}
+	h["alg"] = strings.ToUpper(alg)
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encoding claims: %w", err)
+	}
+	input := encoding.EncodeToString(headerJSON) + "." + encoding.EncodeToString(claimsJSON)
+
+	sig, err := a.sign(input, key)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encoding.EncodeToString(sig), nil
+}
+
+func (a algorithm) sign(input, key string) ([]byte, error) {
+	digest := a.digest(input)
+
+	switch a.family {
+	case "HS":
+		mac := hmac.New(a.hash.New, []byte(key))
+		mac.Write([]byte(input))
+		return mac.Sum(nil), nil
+
+	case "RS":
+		priv, err := privateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := priv.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("RS algorithms need an RSA private key")
+		}
+		return rsa.SignPKCS1v15(rand.Reader, rsaKey, a.hash, digest)
+
+	default:
+		priv, err := privateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := priv.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("ES algorithms need an ECDSA private key")
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest)
+		if err != nil {
+			return nil, err
+		}
+		// JWS signatures are r and s as fixed-size big-endian integers
+		sig := make([]byte, 2*a.size)
+		r.FillBytes(sig[:a.size])
+		s.FillBytes(sig[a.size:])
+		return sig, nil
+	}
+}
+
+func (a algorithm) digest(input string) []byte {
+	h := a.hash.New()
+	h.Write([]byte(input))
+	return h.Sum(nil)
+}
+
+// Parse decodes a token without verifying it. A "Bearer " prefix is ignored.
+func Parse(token string) (*Token, error) {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("not a JWT: expected 3 parts, got %d", len(parts))
+	}
+
+	t := &Token{signingInput: parts[0] + "." + parts[1]}
+	headerJSON, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("decoding header: %w", err)
+	}
+	if err := json.Unmarshal(headerJSON, &t.Header); err != nil {
+		return nil, fmt.Errorf("decoding header: %w", err)
+	}
+	if t.Payload, err = encoding.DecodeString(parts[1]); err != nil {
+		return nil, fmt.Errorf("decoding claims: %w", err)
+	}
+	if err := json.Unmarshal(t.Payload, &t.Claims); err != nil {
+		return nil, fmt.Errorf("decoding claims: %w", err)
+	}
+	if t.signature, err = encoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("decoding signature: %w", err)
+	}
+	return t, nil
+}
+
+// Verify checks the token's signature with key: the HMAC secret for HS
+// algorithms, or a PEM public key or certificate for RS and ES ones. The
+// token must use alg, or when alg is empty an algorithm for the key's type;
+// its own alg header never decides how it is verified, so a PEM key can't
+// be passed off as an HMAC secret.
+func (t *Token) Verify(alg, key string) error {
+	family := "HS"
+	var pub crypto.PublicKey
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		var err error
+		if pub, err = publicKey(key); err != nil {
+			return err
+		}
+		switch pub.(type) {
+		case *rsa.PublicKey:
+			family = "RS"
+		case *ecdsa.PublicKey:
+			family = "ES"
+		default:
+			return fmt.Errorf("unsupported public key type %T", pub)
+		}
+	}
+
+	if alg != "" && !strings.EqualFold(alg, t.Algorithm()) {
+		return fmt.Errorf("token uses %s, expected %s", t.Algorithm(), alg)
+	}
+	a, err := lookup(t.Algorithm())
+	if err != nil {
+		return err
+	}
+	if a.family != family {
+		switch family {
+		case "HS":
+			return fmt.Errorf("%s needs a PEM public key", t.Algorithm())
+		case "RS":
+			return fmt.Errorf("%s can't be verified with an RSA key", t.Algorithm())
+		}
+		return fmt.Errorf("%s can't be verified with an ECDSA key", t.Algorithm())
+	}
+	digest := a.digest(t.signingInput)
+
+	switch a.family {
+	case "HS":
+		mac := hmac.New(a.hash.New, []byte(key))
+		mac.Write([]byte(t.signingInput))
+		if !hmac.Equal(mac.Sum(nil), t.signature) {
+			return errors.New("signature does not match")
+		}
+
+	case "RS":
+		if err := rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), a.hash, digest, t.signature); err != nil {
+			return errors.New("signature does not match")
+		}
+
+	default:
+		if len(t.signature) != 2*a.size {
+			return errors.New("signature does not match")
+		}
+		r := new(big.Int).SetBytes(t.signature[:a.size])
+		s := new(big.Int).SetBytes(t.signature[a.size:])
+		if !ecdsa.Verify(pub.(*ecdsa.PublicKey), digest, r, s) {
+			return errors.New("signature does not match")
+		}
+	}
+	return nil
+}
+
+// privateKey parses a PEM-encoded RSA or ECDSA private key
+func privateKey(data string) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %q", block.Type)
+}
+
+// publicKey parses a PEM-encoded public key or certificate. A private key
+// is accepted too, standing in for its public half.
+func publicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	if key, err := privateKey(data); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer.Public(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported public key type %q", block.Type)
+}
@@ -0,0 +1,75 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// prepareRequests checks each test sets at most one kind of body and makes
+// the files it uploads and its certificates relative to dir, the suite's
+// directory
+func prepareRequests(tests []models.TestCase, dir string) error {
+	for i := range tests {
+		req := &tests[i].Request
+		if err := req.CheckBody(); err != nil {
+			return fmt.Errorf("test %q: %w", tests[i].Name, err)
+		}
+
+		var err error
+		if req.BodyFile, err = localPath("body_file", req.BodyFile, dir); err != nil {
+			return fmt.Errorf("test %q: %w", tests[i].Name, err)
+		}
+		if req.Multipart != nil {
+			// Expanded tests share their parts, so edit a copy
+			parts := make(models.Multipart, len(req.Multipart))
+			for j, part := range req.Multipart {
+				if part.File, err = localPath("multipart file", part.File, dir); err != nil {
+					return fmt.Errorf("test %q: %w", tests[i].Name, err)
+				}
+				parts[j] = part
+			}
+			req.Multipart = parts
+		}
+
+		if tests[i].HTTP, err = httpPaths(tests[i].HTTP, dir); err != nil {
+			return fmt.Errorf("test %q: %w", tests[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// httpPaths returns a copy of opts with its certificate files relative to dir
+func httpPaths(opts *models.HTTPOptions, dir string) (*models.HTTPOptions, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	resolved := *opts
+	var err error
+	if resolved.CACert, err = localPath("ca_cert", opts.CACert, dir); err != nil {
+		return nil, err
+	}
+	if resolved.ClientCert, err = localPath("client_cert", opts.ClientCert, dir); err != nil {
+		return nil, err
+	}
+	if resolved.ClientKey, err = localPath("client_key", opts.ClientKey, dir); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// localPath resolves a path the suite reads from this machine against dir,
+// the suite's directory. Suites without a directory, such as those
+// submitted through the API, may not read files at all.
+func localPath(field, path, dir string) (string, error) {
+	switch {
+	case path == "":
+		return "", nil
+	case dir == "":
+		return "", fmt.Errorf("%s: reading files is only allowed in suite files", field)
+	case filepath.IsAbs(path):
+		return path, nil
+	}
+	return filepath.Join(dir, path), nil
+}
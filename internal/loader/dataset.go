@@ -0,0 +1,164 @@
+package loader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dawgdevv/probe/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// expandTests replaces every test that has each or matrix with one copy per
+// row, named after the row's values. Dependencies on an expanded test are
+// rewritten to depend on all of its copies. Dataset files are read relative
+// to dir.
+func expandTests(tests []models.TestCase, dir string) ([]models.TestCase, error) {
+	var out []models.TestCase
+	expanded := make(map[string][]string)
+
+	for _, test := range tests {
+		if test.Each == nil && len(test.Matrix) == 0 {
+			out = append(out, test)
+			continue
+		}
+
+		rows, err := testRows(test, dir)
+		if err != nil {
+			return nil, fmt.Errorf("test %q: %w", test.Name, err)
+		}
+
+		for _, row := range rows {
+			copied := test
+			copied.Each, copied.Matrix = nil, nil
+			copied.Vars = make(map[string]string, len(test.Vars)+len(row))
+			for k, v := range test.Vars {
+				copied.Vars[k] = v
+			}
+
+			values := make([]string, len(row))
+			for i, col := range row {
+				copied.Vars[col.Name] = col.Value
+				values[i] = col.Value
+			}
+			copied.Name = fmt.Sprintf("%s [%s]", test.Name, strings.Join(values, ", "))
+
+			expanded[test.Name] = append(expanded[test.Name], copied.Name)
+			out = append(out, copied)
+		}
+	}
+
+	if len(expanded) == 0 {
+		return out, nil
+	}
+
+	for i, test := range out {
+		if len(test.DependsOn) == 0 {
+			continue
+		}
+		var deps []string
+		for _, dep := range test.DependsOn {
+			if names, ok := expanded[dep]; ok {
+				deps = append(deps, names...)
+			} else {
+				deps = append(deps, dep)
+			}
+		}
+		out[i].DependsOn = deps
+	}
+
+	return out, nil
+}
+
+// testRows combines a test's dataset rows with its matrix: every row is
+// paired with every matrix combination
+func testRows(test models.TestCase, dir string) ([]models.Row, error) {
+	rows := []models.Row{nil}
+	if test.Each != nil {
+		var err error
+		rows, err = datasetRows(test.Each, dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("each has no rows")
+		}
+	}
+
+	if len(test.Matrix) == 0 {
+		return rows, nil
+	}
+
+	var combined []models.Row
+	for _, row := range rows {
+		for _, combo := range test.Matrix.Rows() {
+			combined = append(combined, append(append(models.Row{}, row...), combo...))
+		}
+	}
+	return combined, nil
+}
+
+// datasetRows returns the inline rows, or reads them from the dataset file
+func datasetRows(ds *models.Dataset, dir string) ([]models.Row, error) {
+	if ds.File == "" {
+		return ds.Rows, nil
+	}
+
+	path, err := localPath("each file", ds.File, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading dataset: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err := csvRows(data)
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.File, err)
+		}
+		return rows, nil
+	case ".json", ".yaml", ".yml":
+		// JSON is valid YAML, and decoding it as such keeps the key order
+		var file models.Dataset
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.File, err)
+		}
+		if file.File != "" {
+			return nil, fmt.Errorf("dataset %s: expected a list of rows", ds.File)
+		}
+		return file.Rows, nil
+	}
+
+	return nil, fmt.Errorf("dataset %s: unsupported file type (use .csv, .json, .yaml or .yml)", ds.File)
+}
+
+// csvRows reads a CSV file whose first record names the columns
+func csvRows(data []byte) ([]models.Row, error) {
+	r := csv.NewReader(strings.NewReader(string(data)))
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	header := records[0]
+	rows := make([]models.Row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(models.Row, len(header))
+		for i, name := range header {
+			row[i] = models.Column{Name: strings.TrimSpace(name), Value: record[i]}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,76 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// EnvironmentsFile is the sidecar file holding environments shared by the
+// suites in its directory and below
+const EnvironmentsFile = "probe.env.yaml"
+
+// addSidecarEnvironments merges the nearest probe.env.yaml at or above dir
+// into the suite's environments. Variables set by the suite itself win.
+func addSidecarEnvironments(suite *models.TestSuite, refs config.References) error {
+	path, err := findUp(refs.Dir, EnvironmentsFile)
+	if err != nil || path == "" {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// File references in the sidecar are relative to the sidecar itself
+	refs.Dir = filepath.Dir(path)
+
+	var shared map[string]map[string]string
+	if err := decode(data, refs, &shared); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if suite.Environments == nil {
+		suite.Environments = make(map[string]map[string]string, len(shared))
+	}
+	for name, vars := range shared {
+		merged := make(map[string]string, len(vars))
+		for k, v := range vars {
+			merged[k] = v
+		}
+		for k, v := range suite.Environments[name] {
+			merged[k] = v
+		}
+		suite.Environments[name] = merged
+	}
+	return nil
+}
+
+// findUp returns the path of the named file in dir or the nearest parent
+// directory that has one, or "" when there is none
+func findUp(dir, name string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
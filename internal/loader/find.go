@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FindSuites expands files, directories and glob patterns into the list of
+// suite files to run, without duplicates and in the order given. Directories
+// are searched recursively for .yaml and .yml files; files found that way are
+// skipped unless they have a tests list, so datasets and other YAML living
+// beside the suites are left alone.
+func FindSuites(args []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, arg := range args {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", arg)
+			}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+
+			found, err := suitesInDir(match)
+			if err != nil {
+				return nil, err
+			}
+			if len(found) == 0 {
+				return nil, fmt.Errorf("no test suites found in %s", match)
+			}
+			for _, path := range found {
+				add(path)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// suitesInDir returns the suite files under dir in lexical order
+func suitesInDir(dir string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			if d.Name() != EnvironmentsFile && isSuite(path) {
+				found = append(found, path)
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// isSuite reports whether the file looks like a suite: a mapping with a
+// tests key. Files that don't parse are included so their errors get reported.
+func isSuite(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return true
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return false
+	}
+	root := doc.Content[0]
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value == "tests" {
+			return true
+		}
+	}
+	return false
+}
@@ -1,33 +1,147 @@
 package loader
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 
+	"github.com/dawgdevv/probe/internal/config"
 	"github.com/dawgdevv/probe/pkg/models"
 	"gopkg.in/yaml.v3"
 )
 
-func LoadSuite(path string) (*models.TestSuite, error) {
+// DotEnvFile is loaded automatically from the suite's directory
+const DotEnvFile = ".env"
+
+// LoadSuite reads a suite file. {{env.NAME}} references are resolved from
+// the process environment, then from envFiles and a .env file beside the
+// suite, with the files given first taking precedence.
+func LoadSuite(path string, envFiles ...string) (*models.TestSuite, error) {
 	data, err := os.ReadFile(path)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var suite models.TestSuite
-	if err := yaml.Unmarshal(data, &suite); err != nil {
+	dir := filepath.Dir(path)
+	dotEnv, err := loadDotEnv(append(envFiles, filepath.Join(dir, DotEnvFile)))
+	if err != nil {
 		return nil, err
 	}
+	refs := config.References{Local: true, DotEnv: dotEnv, Dir: dir}
 
-	return &suite, nil
+	suite, err := parseSuite(data, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addSidecarEnvironments(suite, refs); err != nil {
+		return nil, err
+	}
+
+	return suite, nil
 }
 
-// LoadSuiteFromString parses a YAML test suite from a string
+// LoadSuiteFromString parses a YAML test suite from a string, such as one
+// submitted through the API. Its env and file references are refused, since
+// they would read this process's environment and files.
 func LoadSuiteFromString(yamlContent string) (*models.TestSuite, error) {
+	return parseSuite([]byte(yamlContent), config.References{})
+}
+
+// parseSuite decodes a suite, resolving env and file references, expands
+// data-driven tests and validates their request bodies and the dependencies
+// between them
+func parseSuite(data []byte, refs config.References) (*models.TestSuite, error) {
 	var suite models.TestSuite
-	if err := yaml.Unmarshal([]byte(yamlContent), &suite); err != nil {
+	if err := decode(data, refs, &suite); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if suite.Setup, err = expandTests(suite.Setup, refs.Dir); err != nil {
+		return nil, err
+	}
+	if suite.Tests, err = expandTests(suite.Tests, refs.Dir); err != nil {
+		return nil, err
+	}
+	if suite.Teardown, err = expandTests(suite.Teardown, refs.Dir); err != nil {
+		return nil, err
+	}
+
+	if suite.HTTP, err = httpPaths(suite.HTTP, refs.Dir); err != nil {
+		return nil, err
+	}
+	for _, tests := range [][]models.TestCase{suite.Setup, suite.Tests, suite.Teardown} {
+		if err := prepareRequests(tests, refs.Dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := suite.CheckDependencies(); err != nil {
 		return nil, err
 	}
 
 	return &suite, nil
 }
+
+// decode unmarshals YAML into out after resolving the references in every
+// scalar of the document
+func decode(data []byte, refs config.References, out interface{}) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err := resolveReferences(&doc, refs); err != nil {
+		return err
+	}
+	if doc.Kind == 0 {
+		return nil
+	}
+	return doc.Decode(out)
+}
+
+func resolveReferences(node *yaml.Node, refs config.References) error {
+	if node.Kind == yaml.ScalarNode {
+		val, err := refs.Resolve(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = val
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := resolveReferences(child, refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDotEnv reads .env files, earlier ones taking precedence. Missing
+// files are ignored.
+func loadDotEnv(paths []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := config.ParseDotEnv(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for k, v := range parsed {
+			if _, set := vars[k]; !set {
+				vars[k] = v
+			}
+		}
+	}
+	return vars, nil
+}
@@ -1,6 +1,10 @@
 package service
 
-import "github.com/dawgdevv/probe/internal/executor"
+import (
+	"time"
+
+	"github.com/dawgdevv/probe/internal/executor"
+)
 
 // ResultCollector defines the interface for collecting test results
 type ResultCollector interface {
@@ -15,4 +19,17 @@ type ProgressCallback func(result executor.Result)
 type RunOptions struct {
 	MaxConcurrent    int
 	ProgressCallback ProgressCallback
+
+	// Timeout replaces the suite's default request timeout when set
+	Timeout time.Duration
+
+	// SuiteTimeout replaces the suite's overall deadline when set
+	SuiteTimeout time.Duration
+
+	// Seed, when set, makes generated values such as {{uuid}} repeatable
+	Seed *int64
+
+	// Limiter, when shared between runners, bounds the requests in flight
+	// across all of their suites on top of each suite's MaxConcurrent
+	Limiter Limiter
 }
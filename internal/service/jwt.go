@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/internal/jwt"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// mintTokens signs the suite's JWTs and adds each to vars under its name
+func mintTokens(suite *models.TestSuite, vars map[string]interface{}, seed *int64) error {
+	for _, name := range slices.Sorted(maps.Keys(suite.JWT)) {
+		token, err := mintToken(suite.JWT[name], config.NewEvaluator(vars, seed, "jwt:"+name))
+		if err != nil {
+			return fmt.Errorf("minting jwt %s: %w", name, err)
+		}
+		vars[name] = token
+	}
+	return nil
+}
+
+func mintToken(spec models.JWT, vars *config.Evaluator) (string, error) {
+	key, err := vars.Substitute(spec.Key)
+	if err != nil {
+		return "", err
+	}
+	header, err := resolveMap(vars, spec.Header)
+	if err != nil {
+		return "", err
+	}
+	claims, err := resolveMap(vars, spec.Claims)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = now.Unix()
+	}
+	if _, ok := claims["exp"]; !ok && spec.ExpiresIn > 0 {
+		claims["exp"] = now.Add(spec.ExpiresIn.Std()).Unix()
+	}
+
+	alg := spec.Algorithm
+	if alg == "" {
+		alg = "HS256"
+	}
+	return jwt.Sign(alg, key, header, claims)
+}
+
+// resolveMap resolves the {{ }} expressions throughout m, keeping the type
+// of values that are exactly one expression
+func resolveMap(vars *config.Evaluator, m map[string]interface{}) (map[string]interface{}, error) {
+	if m == nil {
+		return make(map[string]interface{}), nil
+	}
+	resolved, err := vars.ResolveValue(m)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dawgdevv/probe/internal/executor"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// Limiter caps the number of requests in flight across every runner that
+// shares it, so suites run side by side draw from one concurrency budget
+type Limiter chan struct{}
+
+// NewLimiter creates a limiter allowing n requests at once
+func NewLimiter(n int) Limiter {
+	if n <= 0 {
+		n = 1
+	}
+	return make(Limiter, n)
+}
+
+// acquire waits for a free slot and reports whether it took one. A nil
+// limiter never blocks and takes nothing; nor is a slot taken once ctx is
+// done, leaving the request to fail on the cancelled context.
+func (l Limiter) acquire(ctx context.Context) bool {
+	if l == nil {
+		return false
+	}
+	select {
+	case l <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// runTest runs a test while holding a slot of the limiter
+func (l Limiter) runTest(ctx context.Context, baseURL string, env map[string]interface{}, test models.TestCase, opts executor.Options) executor.Result {
+	if l.acquire(ctx) {
+		defer l.release()
+	}
+	return executor.RunTest(ctx, baseURL, env, test, opts)
+}
+
+func (l Limiter) release() {
+	<-l
+}
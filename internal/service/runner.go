@@ -1,8 +1,9 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sync"
 
 	"github.com/dawgdevv/probe/internal/config"
 	"github.com/dawgdevv/probe/internal/executor"
@@ -22,8 +23,31 @@ func NewRunner(options RunOptions) *Runner {
 	return &Runner{options: options}
 }
 
-// RunSuite executes all tests in a suite and returns the results
+// HookError reports a setup or teardown step that failed
+type HookError struct {
+	Phase string
+	Step  string
+	Err   error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s step %q failed: %v", e.Phase, e.Step, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// RunSuite executes all tests in a suite and returns the results in file
+// order. Setup and teardown failures are returned as *HookError alongside
+// the results.
 func (r *Runner) RunSuite(suite *models.TestSuite) ([]executor.Result, error) {
+	return r.RunSuiteContext(context.Background(), suite)
+}
+
+// RunSuiteContext is like RunSuite but stops starting tests and cancels
+// in-flight requests once ctx is done or the suite deadline passes
+func (r *Runner) RunSuiteContext(ctx context.Context, suite *models.TestSuite) ([]executor.Result, error) {
 	// Resolve inter-variable references in env
 	resolvedEnv := config.ResolveEnv(suite.Env)
 
@@ -32,60 +56,187 @@ func (r *Runner) RunSuite(suite *models.TestSuite) ([]executor.Result, error) {
 		return nil, fmt.Errorf("base_url not defined in env")
 	}
 
-	// Buffered channel to collect all results
-	resultsChan := make(chan executor.Result, len(suite.Tests))
+	prereqs, err := suite.Prerequisites()
+	if err != nil {
+		return nil, err
+	}
 
-	// Adjust concurrency limit based on test count
+	// Captured values and session cookies only make sense in file order, so
+	// chained suites without explicit dependencies run one test at a time
 	maxConcurrent := r.options.MaxConcurrent
-	if len(suite.Tests) < maxConcurrent {
-		maxConcurrent = len(suite.Tests)
+	if suite.Sequential || ((usesCaptures(suite) || usesSessions(suite)) && !usesDependencies(suite)) {
+		maxConcurrent = 1
 	}
-	sem := make(chan struct{}, maxConcurrent)
 
-	var wg sync.WaitGroup
+	suiteTimeout := suite.Config.SuiteTimeout.Std()
+	if r.options.SuiteTimeout > 0 {
+		suiteTimeout = r.options.SuiteTimeout
+	}
+	if suiteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, suiteTimeout)
+		defer cancel()
+	}
 
-	// Run tests in parallel with controlled concurrency
-	for _, test := range suite.Tests {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore
+	// Every result is labelled with the suite's name, so runs of several
+	// suites can be told apart, and has secrets masked before anyone sees it
+	secrets := newSecretMask(suite.Secrets, resolvedEnv)
+	report := func(result executor.Result) executor.Result {
+		result.Suite = suite.Name
+		result = secrets.apply(result)
+		if r.options.ProgressCallback != nil {
+			r.options.ProgressCallback(result)
+		}
+		return result
+	}
+
+	vars := config.TextVars(resolvedEnv)
+	jars := newSessions(suite.Session)
+
+	// Per-run state goes to the executor beside each test, leaving the
+	// suite's definitions untouched
+	options := func(test models.TestCase) executor.Options {
+		return executor.Options{Seed: r.options.Seed, Jar: jars.jar(test.Session)}
+	}
+
+	if err := mintTokens(suite, vars, r.options.Seed); err != nil {
+		return nil, err
+	}
+
+	var hookErrs []error
+	var results []executor.Result
+
+	if err := r.runHooks(ctx, executor.PhaseSetup, baseURL, vars, r.withDefaults(suite, suite.Setup), options, report); err != nil {
+		// Running the tests against a half-seeded system would only produce
+		// confusing failures, so skip them all
+		hookErrs = append(hookErrs, err)
+		for _, test := range suite.Tests {
+			results = append(results, report(executor.Skip(test.Name, "setup failed")))
+		}
+	} else {
+		s := newScheduler(r.withDefaults(suite, suite.Tests), prereqs, maxConcurrent, r.options.Limiter, options, func(result executor.Result) { report(result) })
+		results, err = s.run(ctx, baseURL, vars)
+		if err != nil {
+			hookErrs = append(hookErrs, err)
+		}
+		vars = s.vars
+	}
+
+	// Teardown must run even when the suite was cancelled or timed out
+	if err := r.runHooks(context.WithoutCancel(ctx), executor.PhaseTeardown, baseURL, vars, r.withDefaults(suite, suite.Teardown), options, report); err != nil {
+		hookErrs = append(hookErrs, err)
+	}
+
+	// Mask again now that every captured secret is known
+	for i := range results {
+		results[i].Suite = suite.Name
+		results[i] = secrets.apply(results[i])
+	}
+
+	return results, errors.Join(hookErrs...)
+}
 
-		go func(t models.TestCase) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore
+// runHooks runs setup or teardown steps in order, merging their captures
+// into vars and reporting each result. Setup stops at the first failed step; teardown runs every step.
+func (r *Runner) runHooks(ctx context.Context, phase, baseURL string, vars map[string]interface{}, steps []models.TestCase, options func(models.TestCase) executor.Options, report func(executor.Result) executor.Result) error {
+	var errs []error
+	for _, step := range steps {
+		result := r.options.Limiter.runTest(ctx, baseURL, vars, step, options(step))
+		result.Phase = phase
+		for k, v := range result.Captures {
+			vars[k] = v
+		}
 
-			result := executor.RunTest(baseURL, resolvedEnv, t)
+		result = report(result)
 
-			// Send to progress callback if configured
-			if r.options.ProgressCallback != nil {
-				r.options.ProgressCallback(result)
+		if !result.Passed {
+			errs = append(errs, &HookError{Phase: phase, Step: step.Name, Err: result.Error})
+			if phase == executor.PhaseSetup {
+				break
 			}
+		}
+	}
+	return errors.Join(errs...)
+}
 
-			resultsChan <- result
-		}(test)
+// withDefaults returns copies of tests with the default request timeout
+// (from --timeout, or the suite config), the suite retry policy and auth
+// filled in where a test sets none, and the suite's HTTP options under
+// their own
+func (r *Runner) withDefaults(suite *models.TestSuite, cases []models.TestCase) []models.TestCase {
+	timeout := suite.Config.Timeout
+	if r.options.Timeout > 0 {
+		timeout = models.Duration(r.options.Timeout)
 	}
 
-	// Close results channel when all goroutines finish
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
+	tests := make([]models.TestCase, len(cases))
+	for i, test := range cases {
+		if test.Timeout <= 0 {
+			test.Timeout = timeout
+		}
+		if test.Retry == nil {
+			test.Retry = suite.Config.Retry
+		}
+		if test.Auth == nil {
+			test.Auth = suite.Auth
+		}
+		test.HTTP = suite.HTTP.Merge(test.HTTP)
+		tests[i] = test
+	}
+	return tests
+}
 
-	// Collect all results
-	var results []executor.Result
-	for result := range resultsChan {
-		results = append(results, result)
+// usesCaptures reports whether any test in the suite captures values
+func usesCaptures(suite *models.TestSuite) bool {
+	for _, test := range suite.Tests {
+		if len(test.Capture) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// usesSessions reports whether any test in the suite shares a cookie jar
+func usesSessions(suite *models.TestSuite) bool {
+	if suite.Session {
+		return true
+	}
+	for _, test := range suite.Tests {
+		if test.Session != "" && test.Session != models.NoSession {
+			return true
+		}
 	}
+	return false
+}
 
-	return results, nil
+// usesDependencies reports whether any test in the suite declares depends_on
+func usesDependencies(suite *models.TestSuite) bool {
+	for _, test := range suite.Tests {
+		if len(test.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // CountFailures returns the number of failed tests in the results
 func CountFailures(results []executor.Result) int {
 	failed := 0
 	for _, result := range results {
-		if !result.Passed {
+		if !result.Passed && !result.Skipped {
 			failed++
 		}
 	}
 	return failed
 }
+
+// CountSkipped returns the number of tests that were skipped
+func CountSkipped(results []executor.Result) int {
+	skipped := 0
+	for _, result := range results {
+		if result.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dawgdevv/probe/internal/executor"
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// scheduler runs a suite's tests as a dependency graph. Tests whose
+// prerequisites have all finished are started lowest index first, up to
+// maxConcurrent at a time, and tests whose prerequisites did not pass are
+// skipped without being run.
+type scheduler struct {
+	tests         []models.TestCase
+	prereqs       [][]int
+	maxConcurrent int
+	limiter       Limiter
+	options       func(models.TestCase) executor.Options
+	progress      ProgressCallback
+
+	dependents [][]int
+	waiting    []int    // unfinished prerequisites per test
+	blockedBy  []string // first prerequisite that did not pass
+	results    []executor.Result
+	vars       map[string]interface{}
+}
+
+type completion struct {
+	index  int
+	result executor.Result
+}
+
+func newScheduler(tests []models.TestCase, prereqs [][]int, maxConcurrent int, limiter Limiter, options func(models.TestCase) executor.Options, progress ProgressCallback) *scheduler {
+	s := &scheduler{
+		tests:         tests,
+		prereqs:       prereqs,
+		maxConcurrent: maxConcurrent,
+		limiter:       limiter,
+		options:       options,
+		progress:      progress,
+		dependents:    make([][]int, len(tests)),
+		waiting:       make([]int, len(tests)),
+		blockedBy:     make([]string, len(tests)),
+		results:       make([]executor.Result, len(tests)),
+	}
+
+	for i, deps := range prereqs {
+		s.waiting[i] = len(deps)
+		for _, j := range deps {
+			s.dependents[j] = append(s.dependents[j], i)
+		}
+	}
+
+	return s
+}
+
+// run executes every test and returns the results in file order
+func (s *scheduler) run(ctx context.Context, baseURL string, env map[string]interface{}) ([]executor.Result, error) {
+	s.vars = make(map[string]interface{}, len(env))
+	for k, v := range env {
+		s.vars[k] = v
+	}
+
+	var ready []int
+	for i := range s.tests {
+		if s.waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	done := make(chan completion, len(s.tests))
+	running := 0
+	finished := 0
+
+	for finished < len(s.tests) {
+		for len(ready) > 0 && running < s.maxConcurrent {
+			i := ready[0]
+			ready = ready[1:]
+
+			if dep := s.blockedBy[i]; dep != "" {
+				ready = s.finish(ready, i, executor.Skip(s.tests[i].Name, fmt.Sprintf("dependency %q did not pass", dep)))
+				finished++
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				ready = s.finish(ready, i, notStarted(s.tests[i].Name, err))
+				finished++
+				continue
+			}
+
+			// Each test sees the variables captured by everything finished so far
+			vars := make(map[string]interface{}, len(s.vars))
+			for k, v := range s.vars {
+				vars[k] = v
+			}
+
+			running++
+			opts := s.options(s.tests[i])
+			go func(i int, vars map[string]interface{}) {
+				done <- completion{index: i, result: s.limiter.runTest(ctx, baseURL, vars, s.tests[i], opts)}
+			}(i, vars)
+		}
+
+		if running == 0 {
+			if len(ready) > 0 || finished == len(s.tests) {
+				continue
+			}
+			return nil, fmt.Errorf("dependency cycle: %d tests could not be scheduled", len(s.tests)-finished)
+		}
+
+		c := <-done
+		running--
+		for k, v := range c.result.Captures {
+			s.vars[k] = v
+		}
+		ready = s.finish(ready, c.index, c.result)
+		finished++
+	}
+
+	return s.results, nil
+}
+
+// notStarted builds the failed result for a test the suite ran out of time for
+func notStarted(name string, err error) executor.Result {
+	reason := "timed out: suite deadline exceeded before the test started"
+	if errors.Is(err, context.Canceled) {
+		reason = "cancelled: suite run was cancelled before the test started"
+	}
+	return executor.Result{Name: name, Passed: false, Error: errors.New(reason)}
+}
+
+// finish records a result, reports progress and releases the test's
+// dependents, returning the updated ready queue
+func (s *scheduler) finish(ready []int, i int, result executor.Result) []int {
+	s.results[i] = result
+	if s.progress != nil {
+		s.progress(result)
+	}
+
+	released := false
+	for _, d := range s.dependents[i] {
+		if !result.Passed && s.blockedBy[d] == "" {
+			s.blockedBy[d] = s.tests[i].Name
+		}
+		s.waiting[d]--
+		if s.waiting[d] == 0 {
+			ready = append(ready, d)
+			released = true
+		}
+	}
+	if released {
+		sort.Ints(ready)
+	}
+
+	return ready
+}
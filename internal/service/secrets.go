@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/dawgdevv/probe/internal/config"
+	"github.com/dawgdevv/probe/internal/executor"
+)
+
+// secretMask masks the values of a suite's secret variables in results.
+// Secrets captured during the run are masked from the result that
+// captured them onwards.
+type secretMask struct {
+	names    []string
+	redactor *config.Redactor
+}
+
+func newSecretMask(names []string, env map[string]string) *secretMask {
+	m := &secretMask{names: names, redactor: config.NewRedactor()}
+	for _, name := range names {
+		m.redactor.Add(env[name])
+	}
+	return m
+}
+
+// apply learns any secrets the result captured and masks them all
+func (m *secretMask) apply(result executor.Result) executor.Result {
+	if len(m.names) == 0 {
+		return result
+	}
+	for _, name := range m.names {
+		if v, ok := result.Captures[name]; ok {
+			m.redactor.Add(config.Format(v))
+		}
+	}
+	return result.Redact(m.redactor.Redact)
+}
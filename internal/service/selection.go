@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// Selection picks which tests of a suite to run. A test is selected when it
+// passes every criterion that is set; the zero Selection selects them all.
+type Selection struct {
+	// Tags selects tests with at least one of these tags
+	Tags []string `json:"tags"`
+
+	// ExcludeTags drops tests with any of these tags
+	ExcludeTags []string `json:"exclude_tags"`
+
+	// Filter is a regular expression matched against test names
+	Filter string `json:"filter"`
+
+	// Only selects tests by exact name. A data-driven test's name also
+	// selects every row it expands to.
+	Only []string `json:"only"`
+}
+
+// Empty reports whether the selection has no criteria
+func (s Selection) Empty() bool {
+	return len(s.Tags) == 0 && len(s.ExcludeTags) == 0 && s.Filter == "" && len(s.Only) == 0
+}
+
+// Validate checks that the filter compiles and that every name in Only
+// matches a test in at least one of the suites
+func (s Selection) Validate(suites ...*models.TestSuite) error {
+	if _, err := s.compileFilter(); err != nil {
+		return err
+	}
+
+	for _, name := range s.Only {
+		found := false
+		for _, suite := range suites {
+			if anyTest(suite.Tests, func(test models.TestCase) bool { return matchesName(test.Name, name) }) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no test named %q", name)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the suite holding only the selected tests, plus
+// the tests they depend on, and the number of tests left out
+func (s Selection) Apply(suite *models.TestSuite) (*models.TestSuite, int, error) {
+	if s.Empty() {
+		return suite, 0, nil
+	}
+
+	filter, err := s.compileFilter()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	prereqs, err := suite.Prerequisites()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selected := make([]bool, len(suite.Tests))
+	var include func(i int)
+	include = func(i int) {
+		if selected[i] {
+			return
+		}
+		selected[i] = true
+		for _, j := range prereqs[i] {
+			include(j)
+		}
+	}
+
+	for i, test := range suite.Tests {
+		if s.matches(test, filter) {
+			include(i)
+		}
+	}
+
+	filtered := *suite
+	filtered.Tests = nil
+	for i, test := range suite.Tests {
+		if selected[i] {
+			filtered.Tests = append(filtered.Tests, test)
+		}
+	}
+
+	return &filtered, len(suite.Tests) - len(filtered.Tests), nil
+}
+
+func (s Selection) compileFilter() (*regexp.Regexp, error) {
+	if s.Filter == "" {
+		return nil, nil
+	}
+	filter, err := regexp.Compile(s.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", s.Filter, err)
+	}
+	return filter, nil
+}
+
+// matches checks a single test against the selection, ignoring dependencies
+func (s Selection) matches(test models.TestCase, filter *regexp.Regexp) bool {
+	if len(s.Only) > 0 && !anyString(s.Only, func(name string) bool { return matchesName(test.Name, name) }) {
+		return false
+	}
+	if filter != nil && !filter.MatchString(test.Name) {
+		return false
+	}
+	if len(s.Tags) > 0 && !anyString(s.Tags, test.HasTag) {
+		return false
+	}
+	if anyString(s.ExcludeTags, test.HasTag) {
+		return false
+	}
+	return true
+}
+
+// matchesName reports whether a test is the named one or a row expanded from it
+func matchesName(testName, name string) bool {
+	return testName == name || strings.HasPrefix(testName, name+" [")
+}
+
+func anyString(values []string, pred func(string) bool) bool {
+	for _, v := range values {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyTest(tests []models.TestCase, pred func(models.TestCase) bool) bool {
+	for _, test := range tests {
+		if pred(test) {
+			return true
+		}
+	}
+	return false
+}
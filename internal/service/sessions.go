@@ -0,0 +1,32 @@
+package service
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/dawgdevv/probe/pkg/models"
+)
+
+// sessions holds the cookie jars of one suite run, one per session name.
+// The unnamed session is the suite's own, used when it sets session: true.
+type sessions struct {
+	shared bool
+	jars   map[string]http.CookieJar
+}
+
+func newSessions(shared bool) *sessions {
+	return &sessions{shared: shared, jars: make(map[string]http.CookieJar)}
+}
+
+// jar returns the jar of the named session, or nil when the test has none
+func (s *sessions) jar(name string) http.CookieJar {
+	if name == models.NoSession || (name == "" && !s.shared) {
+		return nil
+	}
+	if jar, ok := s.jars[name]; ok {
+		return jar
+	}
+	jar, _ := cookiejar.New(nil) // never fails without options
+	s.jars[name] = jar
+	return jar
+}
@@ -9,7 +9,28 @@ import (
 //go:embed schema.sql
 var schemaSQL string
 
-const currentSchemaVersion = 1
+const currentSchemaVersion = 3
+
+// migrations upgrade an existing database one schema version at a time.
+// schema.sql always describes the latest version, so fresh databases skip them.
+var migrations = []struct {
+	version    int
+	statements []string
+}{
+	{
+		version: 2,
+		statements: []string{
+			"ALTER TABLE test_runs ADD COLUMN skipped_tests INTEGER DEFAULT 0",
+			"ALTER TABLE test_results ADD COLUMN skipped BOOLEAN NOT NULL DEFAULT 0",
+		},
+	},
+	{
+		version: 3,
+		statements: []string{
+			"ALTER TABLE test_results ADD COLUMN failures TEXT",
+		},
+	},
+}
 
 // runMigrations initializes the database schema and applies any migrations
 func runMigrations(db *sql.DB) error {
@@ -29,12 +50,39 @@ func runMigrations(db *sql.DB) error {
 		return nil
 	}
 
-	// Future: Add migration logic here for schema updates
-	// For now, we only have version 1 (initial schema)
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := applyMigration(db, m.version, m.statements); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", m.version, err)
+		}
+	}
 
 	return nil
 }
 
+// applyMigration runs a migration's statements in a transaction and records its version
+func applyMigration(db *sql.DB, version int, statements []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // initSchema creates all tables and indexes from schema.sql
 func initSchema(db *sql.DB) error {
 	// Execute schema SQL
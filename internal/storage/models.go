@@ -1,6 +1,9 @@
 package storage
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Project represents a collection of test suites
 type Project struct {
@@ -23,24 +26,27 @@ type TestSuite struct {
 
 // TestRun represents an execution of a test suite
 type TestRun struct {
-	ID          int64      `json:"id"`
-	SuiteID     int64      `json:"suite_id"`
-	StartedAt   time.Time  `json:"started_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	Status      string     `json:"status"`
-	TotalTests  int        `json:"total_tests"`
-	PassedTests int        `json:"passed_tests"`
-	FailedTests int        `json:"failed_tests"`
+	ID           int64      `json:"id"`
+	SuiteID      int64      `json:"suite_id"`
+	StartedAt    time.Time  `json:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	Status       string     `json:"status"`
+	TotalTests   int        `json:"total_tests"`
+	PassedTests  int        `json:"passed_tests"`
+	FailedTests  int        `json:"failed_tests"`
+	SkippedTests int        `json:"skipped_tests"`
 }
 
 // TestResult represents a single test result within a run
 type TestResult struct {
-	ID           int64     `json:"id"`
-	RunID        int64     `json:"run_id"`
-	TestName     string    `json:"test_name"`
-	Passed       bool      `json:"passed"`
-	StatusCode   int       `json:"status_code,omitempty"`
-	ErrorMessage string    `json:"error_message,omitempty"`
-	DurationMs   int64     `json:"duration_ms"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int64           `json:"id"`
+	RunID        int64           `json:"run_id"`
+	TestName     string          `json:"test_name"`
+	Passed       bool            `json:"passed"`
+	Skipped      bool            `json:"skipped"`
+	StatusCode   int             `json:"status_code,omitempty"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	Failures     json.RawMessage `json:"failures,omitempty"`
+	DurationMs   int64           `json:"duration_ms"`
+	CreatedAt    time.Time       `json:"created_at"`
 }
@@ -28,6 +28,7 @@ CREATE TABLE IF NOT EXISTS test_runs (
     total_tests INTEGER DEFAULT 0,
     passed_tests INTEGER DEFAULT 0,
     failed_tests INTEGER DEFAULT 0,
+    skipped_tests INTEGER DEFAULT 0,
     FOREIGN KEY (suite_id) REFERENCES test_suites(id) ON DELETE CASCADE
 );
 
@@ -37,8 +38,10 @@ CREATE TABLE IF NOT EXISTS test_results (
     run_id INTEGER NOT NULL,
     test_name TEXT NOT NULL,
     passed BOOLEAN NOT NULL,
+    skipped BOOLEAN NOT NULL DEFAULT 0,
     status_code INTEGER,
     error_message TEXT,
+    failures TEXT, -- JSON array of individual assertion failures
     duration_ms INTEGER,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (run_id) REFERENCES test_runs(id) ON DELETE CASCADE
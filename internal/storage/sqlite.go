@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -199,9 +200,9 @@ func (s *Store) GetTestRun(id int64) (*TestRun, error) {
 	var completedAt sql.NullTime
 
 	err := s.db.QueryRow(
-		"SELECT id, suite_id, started_at, completed_at, status, total_tests, passed_tests, failed_tests FROM test_runs WHERE id = ?",
+		"SELECT id, suite_id, started_at, completed_at, status, total_tests, passed_tests, failed_tests, skipped_tests FROM test_runs WHERE id = ?",
 		id,
-	).Scan(&run.ID, &run.SuiteID, &run.StartedAt, &completedAt, &run.Status, &run.TotalTests, &run.PassedTests, &run.FailedTests)
+	).Scan(&run.ID, &run.SuiteID, &run.StartedAt, &completedAt, &run.Status, &run.TotalTests, &run.PassedTests, &run.FailedTests, &run.SkippedTests)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test run: %w", err)
@@ -215,10 +216,10 @@ func (s *Store) GetTestRun(id int64) (*TestRun, error) {
 }
 
 // CompleteTestRun marks a test run as completed
-func (s *Store) CompleteTestRun(id int64, status string, passed, failed int) error {
+func (s *Store) CompleteTestRun(id int64, status string, passed, failed, skipped int) error {
 	_, err := s.db.Exec(
-		"UPDATE test_runs SET completed_at = ?, status = ?, passed_tests = ?, failed_tests = ? WHERE id = ?",
-		time.Now(), status, passed, failed, id,
+		"UPDATE test_runs SET completed_at = ?, status = ?, passed_tests = ?, failed_tests = ?, skipped_tests = ? WHERE id = ?",
+		time.Now(), status, passed, failed, skipped, id,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to complete test run: %w", err)
@@ -233,7 +234,7 @@ func (s *Store) ListTestRuns(suiteID int64, limit int) ([]TestRun, error) {
 	}
 
 	rows, err := s.db.Query(
-		"SELECT id, suite_id, started_at, completed_at, status, total_tests, passed_tests, failed_tests FROM test_runs WHERE suite_id = ? ORDER BY started_at DESC LIMIT ?",
+		"SELECT id, suite_id, started_at, completed_at, status, total_tests, passed_tests, failed_tests, skipped_tests FROM test_runs WHERE suite_id = ? ORDER BY started_at DESC LIMIT ?",
 		suiteID, limit,
 	)
 	if err != nil {
@@ -245,7 +246,7 @@ func (s *Store) ListTestRuns(suiteID int64, limit int) ([]TestRun, error) {
 	for rows.Next() {
 		var run TestRun
 		var completedAt sql.NullTime
-		if err := rows.Scan(&run.ID, &run.SuiteID, &run.StartedAt, &completedAt, &run.Status, &run.TotalTests, &run.PassedTests, &run.FailedTests); err != nil {
+		if err := rows.Scan(&run.ID, &run.SuiteID, &run.StartedAt, &completedAt, &run.Status, &run.TotalTests, &run.PassedTests, &run.FailedTests, &run.SkippedTests); err != nil {
 			return nil, fmt.Errorf("failed to scan test run: %w", err)
 		}
 		if completedAt.Valid {
@@ -259,11 +260,17 @@ func (s *Store) ListTestRuns(suiteID int64, limit int) ([]TestRun, error) {
 
 // --- Test Result operations ---
 
-// SaveTestResult saves a single test result
-func (s *Store) SaveTestResult(runID int64, testName string, passed bool, statusCode int, errorMessage string, durationMs int64) error {
+// SaveTestResult saves a single test result. failures is a JSON array of
+// assertion failures, or empty when there are none.
+func (s *Store) SaveTestResult(runID int64, testName string, passed, skipped bool, statusCode int, errorMessage, failures string, durationMs int64) error {
+	var failuresCol sql.NullString
+	if failures != "" {
+		failuresCol = sql.NullString{String: failures, Valid: true}
+	}
+
 	_, err := s.db.Exec(
-		"INSERT INTO test_results (run_id, test_name, passed, status_code, error_message, duration_ms) VALUES (?, ?, ?, ?, ?, ?)",
-		runID, testName, passed, statusCode, errorMessage, durationMs,
+		"INSERT INTO test_results (run_id, test_name, passed, skipped, status_code, error_message, failures, duration_ms) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		runID, testName, passed, skipped, statusCode, errorMessage, failuresCol, durationMs,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save test result: %w", err)
@@ -274,7 +281,7 @@ func (s *Store) SaveTestResult(runID int64, testName string, passed bool, status
 // GetTestResults retrieves all results for a test run
 func (s *Store) GetTestResults(runID int64) ([]TestResult, error) {
 	rows, err := s.db.Query(
-		"SELECT id, run_id, test_name, passed, status_code, error_message, duration_ms, created_at FROM test_results WHERE run_id = ? ORDER BY created_at",
+		"SELECT id, run_id, test_name, passed, skipped, status_code, error_message, failures, duration_ms, created_at FROM test_results WHERE run_id = ? ORDER BY created_at",
 		runID,
 	)
 	if err != nil {
@@ -285,9 +292,13 @@ func (s *Store) GetTestResults(runID int64) ([]TestResult, error) {
 	var results []TestResult
 	for rows.Next() {
 		var result TestResult
-		if err := rows.Scan(&result.ID, &result.RunID, &result.TestName, &result.Passed, &result.StatusCode, &result.ErrorMessage, &result.DurationMs, &result.CreatedAt); err != nil {
+		var failures sql.NullString
+		if err := rows.Scan(&result.ID, &result.RunID, &result.TestName, &result.Passed, &result.Skipped, &result.StatusCode, &result.ErrorMessage, &failures, &result.DurationMs, &result.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan test result: %w", err)
 		}
+		if failures.Valid {
+			result.Failures = json.RawMessage(failures.String)
+		}
 		results = append(results, result)
 	}
 
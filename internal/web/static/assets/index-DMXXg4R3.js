@@ -206,4 +206,4 @@ tests:
       path: /posts/1
     expect:
       status: 200
-`;function Kx(){const{suiteId:l}=io(),i=Number(l),r=Cp(),c=vi(),[o,d]=O.useState(null),{data:h}=xa({queryKey:["suite",i],queryFn:()=>qx(i)}),{data:m}=xa({queryKey:["runs",i],queryFn:()=>Qx(i)}),v=yo({mutationFn:()=>Lx(i),onSuccess:p=>{d(p),c.invalidateQueries({queryKey:["runs",i]}),sl.success(`${p.passed_tests}/${p.total_tests} passed`)},onError:()=>sl.error("Failed to run tests")});return R.jsxs("div",{children:[R.jsxs("div",{className:"flex items-center gap-2 text-xs text-[var(--text-tertiary)] mb-6 font-['JetBrains_Mono'] uppercase tracking-wider",children:[R.jsx(Ge,{to:"/",className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Projects"}),R.jsx("span",{children:"/"}),h&&R.jsxs(R.Fragment,{children:[R.jsx(Ge,{to:`/projects/${h.project_id}`,className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Project"}),R.jsx("span",{children:"/"})]}),R.jsx("span",{className:"text-[var(--text-secondary)]",children:h?.name??"..."})]}),R.jsxs("div",{className:"flex items-center justify-between mb-8",children:[R.jsx("h2",{className:"text-2xl font-bold tracking-tight",children:h?.name}),R.jsx("button",{onClick:()=>v.mutate(),disabled:v.isPending,className:"bg-white hover:bg-[var(--accent-hover)] text-black px-5 py-2.5 rounded text-xs font-semibold transition-all cursor-pointer border-none disabled:opacity-50 uppercase tracking-wider",children:v.isPending?"Running...":"▶ Run Tests"})]}),h&&R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded mb-8 overflow-hidden",children:[R.jsx("div",{className:"px-4 py-2.5 border-b border-[var(--border)] text-[10px] text-[var(--text-tertiary)] font-semibold font-['JetBrains_Mono'] uppercase tracking-widest",children:"Test Definition — YAML"}),R.jsx("pre",{className:"p-4 m-0 text-xs font-['JetBrains_Mono'] text-[var(--text-secondary)] overflow-x-auto whitespace-pre-wrap leading-relaxed",children:h.yaml_content})]}),o&&R.jsxs("div",{className:"mb-8",children:[R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Latest Run"}),R.jsxs("div",{className:"flex gap-3 mb-5",children:[R.jsx(Gc,{label:"Total",value:o.total_tests,color:"var(--text-primary)"}),R.jsx(Gc,{label:"Passed",value:o.passed_tests,color:"var(--success)"}),R.jsx(Gc,{label:"Failed",value:o.failed_tests,color:"var(--danger)"})]}),R.jsx("div",{className:"space-y-1",children:o.results.map((p,g)=>R.jsxs("div",{className:`flex items-center justify-between bg-[var(--bg-secondary)] border rounded px-4 py-3 ${p.Passed?"border-[var(--border)]":"border-[var(--danger)]/30"}`,children:[R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:`w-1.5 h-1.5 rounded-full ${p.Passed?"bg-[var(--success)]":"bg-[var(--danger)]"}`}),R.jsx("span",{className:"text-xs font-medium",children:p.Name})]}),R.jsxs("div",{className:"flex items-center gap-4 text-xs text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:[p.StatusCode>0&&R.jsx("span",{className:"bg-[var(--bg-tertiary)] px-2 py-0.5 rounded text-[10px]",children:p.StatusCode}),R.jsxs("span",{children:[(p.Duration/1e6).toFixed(0),"ms"]}),p.Error&&R.jsx("span",{className:"text-[var(--danger)] text-[10px] max-w-[200px] truncate",children:p.Error})]})]},g))})]}),R.jsxs("div",{children:[R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Run History"}),!m||m.length===0?R.jsx("div",{className:"text-center py-12 border border-dashed border-[var(--border)] rounded",children:R.jsx("p",{className:"text-[var(--text-tertiary)] text-xs",children:'No runs yet — click "Run Tests" to execute this suite'})}):R.jsx("div",{className:"space-y-1",children:m.map(p=>R.jsxs("div",{onClick:()=>r(`/runs/${p.id}`),className:"flex items-center justify-between bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-4 py-3 cursor-pointer transition-all hover:border-[var(--border-hover)] hover:bg-[var(--bg-tertiary)] group",children:[R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:`text-[10px] font-bold px-2 py-1 rounded font-['JetBrains_Mono'] uppercase tracking-wider ${p.status==="passed"?"bg-[var(--success-muted)] text-[var(--success)]":p.status==="failed"?"bg-[var(--danger-muted)] text-[var(--danger)]":"bg-[var(--warning-muted)] text-[var(--warning)]"}`,children:p.status}),R.jsxs("span",{className:"text-xs text-[var(--text-secondary)]",children:[p.passed_tests,"/",p.total_tests," passed"]})]}),R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:"text-[10px] text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:new Date(p.started_at).toLocaleString()}),R.jsx("span",{className:"text-[var(--text-tertiary)] group-hover:text-[var(--text-secondary)] transition-colors text-xs",children:"→"})]})]},p.id))})]})]})}function Gc({label:l,value:i,color:r}){return R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-5 py-3 text-center min-w-[80px]",children:[R.jsx("div",{className:"text-xl font-bold font-['JetBrains_Mono']",style:{color:r},children:i}),R.jsx("div",{className:"text-[10px] text-[var(--text-tertiary)] mt-1 uppercase tracking-widest font-semibold",children:l})]})}function Zx(){const{runId:l}=io(),i=Number(l),{data:r}=xa({queryKey:["run",i],queryFn:()=>Yx(i)}),{data:c}=xa({queryKey:["runResults",i],queryFn:()=>Gx(i)}),o=r&&r.total_tests>0?Math.round(r.passed_tests/r.total_tests*100):0;return R.jsxs("div",{children:[R.jsxs("div",{className:"flex items-center gap-2 text-xs text-[var(--text-tertiary)] mb-6 font-['JetBrains_Mono'] uppercase tracking-wider",children:[R.jsx(Ge,{to:"/",className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Projects"}),R.jsx("span",{children:"/"}),r&&R.jsxs(R.Fragment,{children:[R.jsx(Ge,{to:`/suites/${r.suite_id}`,className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Suite"}),R.jsx("span",{children:"/"})]}),R.jsxs("span",{className:"text-[var(--text-secondary)]",children:["Run #",l]})]}),r&&R.jsxs(R.Fragment,{children:[R.jsxs("div",{className:"flex items-center justify-between mb-8",children:[R.jsxs("h2",{className:"text-2xl font-bold tracking-tight",children:["Run #",r.id]}),R.jsx("span",{className:`text-[10px] font-bold px-3 py-1.5 rounded font-['JetBrains_Mono'] uppercase tracking-wider ${r.status==="passed"?"bg-[var(--success-muted)] text-[var(--success)]":r.status==="failed"?"bg-[var(--danger-muted)] text-[var(--danger)]":"bg-[var(--warning-muted)] text-[var(--warning)]"}`,children:r.status})]}),R.jsxs("div",{className:"grid grid-cols-2 md:grid-cols-5 gap-3 mb-8",children:[R.jsx(fi,{label:"Total",value:r.total_tests}),R.jsx(fi,{label:"Passed",value:r.passed_tests,color:"var(--success)"}),R.jsx(fi,{label:"Failed",value:r.failed_tests,color:"var(--danger)"}),R.jsx(fi,{label:"Pass Rate",value:`${o}%`,color:o===100?"var(--success)":o>50?"var(--text-primary)":"var(--danger)"}),R.jsx(fi,{label:"Started",value:new Date(r.started_at).toLocaleTimeString(),small:!0})]})]}),R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Test Results"}),!c||c.length===0?R.jsx("div",{className:"text-center py-12 border border-dashed border-[var(--border)] rounded",children:R.jsx("p",{className:"text-[var(--text-tertiary)] text-xs",children:"No results available"})}):R.jsx("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded overflow-hidden",children:R.jsxs("table",{className:"w-full text-xs",children:[R.jsx("thead",{children:R.jsxs("tr",{className:"border-b border-[var(--border)]",children:[R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest w-12"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Test"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Status"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Duration"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Error"})]})}),R.jsx("tbody",{children:c.map(d=>R.jsxs("tr",{className:"border-b border-[var(--border)] last:border-none hover:bg-[var(--bg-tertiary)] transition-colors",children:[R.jsx("td",{className:"px-4 py-3",children:R.jsx("span",{className:`w-1.5 h-1.5 rounded-full inline-block ${d.passed?"bg-[var(--success)]":"bg-[var(--danger)]"}`})}),R.jsx("td",{className:"px-4 py-3 font-medium text-[var(--text-primary)]",children:d.test_name}),R.jsx("td",{className:"px-4 py-3",children:d.status_code>0&&R.jsx("span",{className:"bg-[var(--bg-tertiary)] px-2 py-0.5 rounded text-[10px] font-['JetBrains_Mono'] text-[var(--text-secondary)]",children:d.status_code})}),R.jsxs("td",{className:"px-4 py-3 text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:[d.duration_ms,"ms"]}),R.jsx("td",{className:"px-4 py-3 text-[var(--danger)] text-[10px] max-w-[300px] truncate font-['JetBrains_Mono']",children:d.error_message})]},d.id))})]})})]})}function fi({label:l,value:i,color:r,small:c}){return R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-4 py-3 text-center",children:[R.jsx("div",{className:`font-bold font-['JetBrains_Mono'] ${c?"text-xs":"text-xl"}`,style:{color:r??"var(--text-primary)"},children:i}),R.jsx("div",{className:"text-[10px] text-[var(--text-tertiary)] mt-1 uppercase tracking-widest font-semibold",children:l})]})}function Jx(){return R.jsx(Ig,{children:R.jsxs(rl,{path:"/",element:R.jsx(aS,{}),children:[R.jsx(rl,{index:!0,element:R.jsx(Xx,{})}),R.jsx(rl,{path:"projects/:projectId",element:R.jsx(Vx,{})}),R.jsx(rl,{path:"suites/:suiteId",element:R.jsx(Kx,{})}),R.jsx(rl,{path:"runs/:runId",element:R.jsx(Zx,{})})]})})}const Fx=new n1({defaultOptions:{queries:{refetchOnWindowFocus:!1,retry:1}}});I0.createRoot(document.getElementById("root")).render(R.jsx(O.StrictMode,{children:R.jsx(a1,{client:Fx,children:R.jsxs(Rb,{children:[R.jsx(Jx,{}),R.jsx(nS,{position:"bottom-right",toastOptions:{style:{background:"#0a0a0a",color:"#fafafa",border:"1px solid #262626",fontFamily:"DM Sans, system-ui, sans-serif",fontSize:"13px",borderRadius:"4px"}}})]})})}));
+`;function Kx(){const{suiteId:l}=io(),i=Number(l),r=Cp(),c=vi(),[o,d]=O.useState(null),{data:h}=xa({queryKey:["suite",i],queryFn:()=>qx(i)}),{data:m}=xa({queryKey:["runs",i],queryFn:()=>Qx(i)}),v=yo({mutationFn:()=>Lx(i),onSuccess:p=>{d(p),c.invalidateQueries({queryKey:["runs",i]}),sl.success(`${p.passed_tests}/${p.total_tests} passed`)},onError:()=>sl.error("Failed to run tests")});return R.jsxs("div",{children:[R.jsxs("div",{className:"flex items-center gap-2 text-xs text-[var(--text-tertiary)] mb-6 font-['JetBrains_Mono'] uppercase tracking-wider",children:[R.jsx(Ge,{to:"/",className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Projects"}),R.jsx("span",{children:"/"}),h&&R.jsxs(R.Fragment,{children:[R.jsx(Ge,{to:`/projects/${h.project_id}`,className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Project"}),R.jsx("span",{children:"/"})]}),R.jsx("span",{className:"text-[var(--text-secondary)]",children:h?.name??"..."})]}),R.jsxs("div",{className:"flex items-center justify-between mb-8",children:[R.jsx("h2",{className:"text-2xl font-bold tracking-tight",children:h?.name}),R.jsx("button",{onClick:()=>v.mutate(),disabled:v.isPending,className:"bg-white hover:bg-[var(--accent-hover)] text-black px-5 py-2.5 rounded text-xs font-semibold transition-all cursor-pointer border-none disabled:opacity-50 uppercase tracking-wider",children:v.isPending?"Running...":"▶ Run Tests"})]}),h&&R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded mb-8 overflow-hidden",children:[R.jsx("div",{className:"px-4 py-2.5 border-b border-[var(--border)] text-[10px] text-[var(--text-tertiary)] font-semibold font-['JetBrains_Mono'] uppercase tracking-widest",children:"Test Definition — YAML"}),R.jsx("pre",{className:"p-4 m-0 text-xs font-['JetBrains_Mono'] text-[var(--text-secondary)] overflow-x-auto whitespace-pre-wrap leading-relaxed",children:h.yaml_content})]}),o&&R.jsxs("div",{className:"mb-8",children:[R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Latest Run"}),R.jsxs("div",{className:"flex gap-3 mb-5",children:[R.jsx(Gc,{label:"Total",value:o.total_tests,color:"var(--text-primary)"}),R.jsx(Gc,{label:"Passed",value:o.passed_tests,color:"var(--success)"}),R.jsx(Gc,{label:"Failed",value:o.failed_tests,color:"var(--danger)"})]}),R.jsx("div",{className:"space-y-1",children:o.results.map((p,g)=>R.jsxs("div",{className:`flex items-center justify-between bg-[var(--bg-secondary)] border rounded px-4 py-3 ${p.passed?"border-[var(--border)]":"border-[var(--danger)]/30"}`,children:[R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:`w-1.5 h-1.5 rounded-full ${p.passed?"bg-[var(--success)]":"bg-[var(--danger)]"}`}),R.jsx("span",{className:"text-xs font-medium",children:p.name})]}),R.jsxs("div",{className:"flex items-center gap-4 text-xs text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:[p.status_code>0&&R.jsx("span",{className:"bg-[var(--bg-tertiary)] px-2 py-0.5 rounded text-[10px]",children:p.status_code}),R.jsx("span",{children:p.duration}),p.error&&R.jsx("span",{className:"text-[var(--danger)] text-[10px] max-w-[200px] truncate",children:p.error})]})]},g))})]}),R.jsxs("div",{children:[R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Run History"}),!m||m.length===0?R.jsx("div",{className:"text-center py-12 border border-dashed border-[var(--border)] rounded",children:R.jsx("p",{className:"text-[var(--text-tertiary)] text-xs",children:'No runs yet — click "Run Tests" to execute this suite'})}):R.jsx("div",{className:"space-y-1",children:m.map(p=>R.jsxs("div",{onClick:()=>r(`/runs/${p.id}`),className:"flex items-center justify-between bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-4 py-3 cursor-pointer transition-all hover:border-[var(--border-hover)] hover:bg-[var(--bg-tertiary)] group",children:[R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:`text-[10px] font-bold px-2 py-1 rounded font-['JetBrains_Mono'] uppercase tracking-wider ${p.status==="passed"?"bg-[var(--success-muted)] text-[var(--success)]":p.status==="failed"?"bg-[var(--danger-muted)] text-[var(--danger)]":"bg-[var(--warning-muted)] text-[var(--warning)]"}`,children:p.status}),R.jsxs("span",{className:"text-xs text-[var(--text-secondary)]",children:[p.passed_tests,"/",p.total_tests," passed"]})]}),R.jsxs("div",{className:"flex items-center gap-3",children:[R.jsx("span",{className:"text-[10px] text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:new Date(p.started_at).toLocaleString()}),R.jsx("span",{className:"text-[var(--text-tertiary)] group-hover:text-[var(--text-secondary)] transition-colors text-xs",children:"→"})]})]},p.id))})]})]})}function Gc({label:l,value:i,color:r}){return R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-5 py-3 text-center min-w-[80px]",children:[R.jsx("div",{className:"text-xl font-bold font-['JetBrains_Mono']",style:{color:r},children:i}),R.jsx("div",{className:"text-[10px] text-[var(--text-tertiary)] mt-1 uppercase tracking-widest font-semibold",children:l})]})}function Zx(){const{runId:l}=io(),i=Number(l),{data:r}=xa({queryKey:["run",i],queryFn:()=>Yx(i)}),{data:c}=xa({queryKey:["runResults",i],queryFn:()=>Gx(i)}),o=r&&r.total_tests>0?Math.round(r.passed_tests/r.total_tests*100):0;return R.jsxs("div",{children:[R.jsxs("div",{className:"flex items-center gap-2 text-xs text-[var(--text-tertiary)] mb-6 font-['JetBrains_Mono'] uppercase tracking-wider",children:[R.jsx(Ge,{to:"/",className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Projects"}),R.jsx("span",{children:"/"}),r&&R.jsxs(R.Fragment,{children:[R.jsx(Ge,{to:`/suites/${r.suite_id}`,className:"hover:text-[var(--text-primary)] no-underline text-[var(--text-tertiary)] transition-colors",children:"Suite"}),R.jsx("span",{children:"/"})]}),R.jsxs("span",{className:"text-[var(--text-secondary)]",children:["Run #",l]})]}),r&&R.jsxs(R.Fragment,{children:[R.jsxs("div",{className:"flex items-center justify-between mb-8",children:[R.jsxs("h2",{className:"text-2xl font-bold tracking-tight",children:["Run #",r.id]}),R.jsx("span",{className:`text-[10px] font-bold px-3 py-1.5 rounded font-['JetBrains_Mono'] uppercase tracking-wider ${r.status==="passed"?"bg-[var(--success-muted)] text-[var(--success)]":r.status==="failed"?"bg-[var(--danger-muted)] text-[var(--danger)]":"bg-[var(--warning-muted)] text-[var(--warning)]"}`,children:r.status})]}),R.jsxs("div",{className:"grid grid-cols-2 md:grid-cols-5 gap-3 mb-8",children:[R.jsx(fi,{label:"Total",value:r.total_tests}),R.jsx(fi,{label:"Passed",value:r.passed_tests,color:"var(--success)"}),R.jsx(fi,{label:"Failed",value:r.failed_tests,color:"var(--danger)"}),R.jsx(fi,{label:"Pass Rate",value:`${o}%`,color:o===100?"var(--success)":o>50?"var(--text-primary)":"var(--danger)"}),R.jsx(fi,{label:"Started",value:new Date(r.started_at).toLocaleTimeString(),small:!0})]})]}),R.jsx("h3",{className:"text-sm font-semibold mb-4 uppercase tracking-wider text-[var(--text-secondary)]",children:"Test Results"}),!c||c.length===0?R.jsx("div",{className:"text-center py-12 border border-dashed border-[var(--border)] rounded",children:R.jsx("p",{className:"text-[var(--text-tertiary)] text-xs",children:"No results available"})}):R.jsx("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded overflow-hidden",children:R.jsxs("table",{className:"w-full text-xs",children:[R.jsx("thead",{children:R.jsxs("tr",{className:"border-b border-[var(--border)]",children:[R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest w-12"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Test"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Status"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Duration"}),R.jsx("th",{className:"text-left px-4 py-3 text-[10px] text-[var(--text-tertiary)] font-semibold uppercase tracking-widest",children:"Error"})]})}),R.jsx("tbody",{children:c.map(d=>R.jsxs("tr",{className:"border-b border-[var(--border)] last:border-none hover:bg-[var(--bg-tertiary)] transition-colors",children:[R.jsx("td",{className:"px-4 py-3",children:R.jsx("span",{className:`w-1.5 h-1.5 rounded-full inline-block ${d.passed?"bg-[var(--success)]":"bg-[var(--danger)]"}`})}),R.jsx("td",{className:"px-4 py-3 font-medium text-[var(--text-primary)]",children:d.test_name}),R.jsx("td",{className:"px-4 py-3",children:d.status_code>0&&R.jsx("span",{className:"bg-[var(--bg-tertiary)] px-2 py-0.5 rounded text-[10px] font-['JetBrains_Mono'] text-[var(--text-secondary)]",children:d.status_code})}),R.jsxs("td",{className:"px-4 py-3 text-[var(--text-tertiary)] font-['JetBrains_Mono']",children:[d.duration_ms,"ms"]}),R.jsx("td",{className:"px-4 py-3 text-[var(--danger)] text-[10px] max-w-[300px] truncate font-['JetBrains_Mono']",children:d.error_message})]},d.id))})]})})]})}function fi({label:l,value:i,color:r,small:c}){return R.jsxs("div",{className:"bg-[var(--bg-secondary)] border border-[var(--border)] rounded px-4 py-3 text-center",children:[R.jsx("div",{className:`font-bold font-['JetBrains_Mono'] ${c?"text-xs":"text-xl"}`,style:{color:r??"var(--text-primary)"},children:i}),R.jsx("div",{className:"text-[10px] text-[var(--text-tertiary)] mt-1 uppercase tracking-widest font-semibold",children:l})]})}function Jx(){return R.jsx(Ig,{children:R.jsxs(rl,{path:"/",element:R.jsx(aS,{}),children:[R.jsx(rl,{index:!0,element:R.jsx(Xx,{})}),R.jsx(rl,{path:"projects/:projectId",element:R.jsx(Vx,{})}),R.jsx(rl,{path:"suites/:suiteId",element:R.jsx(Kx,{})}),R.jsx(rl,{path:"runs/:runId",element:R.jsx(Zx,{})})]})})}const Fx=new n1({defaultOptions:{queries:{refetchOnWindowFocus:!1,retry:1}}});I0.createRoot(document.getElementById("root")).render(R.jsx(O.StrictMode,{children:R.jsx(a1,{client:Fx,children:R.jsxs(Rb,{children:[R.jsx(Jx,{}),R.jsx(nS,{position:"bottom-right",toastOptions:{style:{background:"#0a0a0a",color:"#fafafa",border:"1px solid #262626",fontFamily:"DM Sans, system-ui, sans-serif",fontSize:"13px",borderRadius:"4px"}}})]})})}));
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Auth authenticates a test's requests. It is written as one of
+//
+//	auth: { basic: { username: u, password: p } }
+//	auth: { bearer: "{{token}}" }
+//	auth: { api_key: { header: X-API-Key, value: "{{key}}" } }
+//	auth: { digest: { username: u, password: p } }
+//	auth: { oauth2: { token_url: ..., client_id: ..., client_secret: ... } }
+//	auth: { hmac: { key: "{{secret}}", header: X-Signature } }
+//	auth: { aws_sigv4: { access_key: ..., secret_key: ..., region: ..., service: s3 } }
+//	auth: none
+//
+// A test's auth replaces the suite's; auth: none sends no credentials.
+type Auth struct {
+	None   bool
+	Basic  *Credentials `yaml:"basic"`
+	Bearer string       `yaml:"bearer"`
+	APIKey *APIKey      `yaml:"api_key"`
+	Digest *Credentials `yaml:"digest"`
+	OAuth2 *OAuth2      `yaml:"oauth2"`
+
+	// Signers compute a signature over the finished request
+	HMAC     *HMAC     `yaml:"hmac"`
+	AWSSigV4 *AWSSigV4 `yaml:"aws_sigv4"`
+}
+
+// Credentials are a username and password
+type Credentials struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// APIKey sends a key in a header or, when Query is set, a query parameter
+type APIKey struct {
+	Header string `yaml:"header"`
+	Query  string `yaml:"query"`
+	Value  string `yaml:"value"`
+}
+
+// OAuth2 fetches a bearer token from TokenURL with the client credentials
+// grant or, when Username is set, the password grant. Tokens are reused
+// until they expire.
+type OAuth2 struct {
+	TokenURL     string   `yaml:"token_url"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+
+	// Username and Password select the password grant
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// ClientAuth is how the client authenticates to the token URL: "basic"
+	// (the default) sends an Authorization header, "body" form fields
+	ClientAuth string `yaml:"client_auth"`
+}
+
+// HMAC signs requests with a keyed hash of StringToSign, a template over
+// the request ({{request.method}}, {{request.uri}}, {{request.body_sha256}},
+// {{request.header.<Name>}}, ...). The signature is sent in Header as
+// Value, a template in which {{signature}} stands for it.
+type HMAC struct {
+	Key          string `yaml:"key"`
+	Algorithm    string `yaml:"algorithm"` // sha256 (default), sha1 or sha512
+	Encoding     string `yaml:"encoding"`  // hex (default) or base64
+	StringToSign string `yaml:"string_to_sign"`
+	Header       string `yaml:"header"` // default Authorization
+	Value        string `yaml:"value"`  // default "{{signature}}"
+}
+
+// AWSSigV4 signs requests with AWS Signature Version 4
+type AWSSigV4 struct {
+	AccessKey    string `yaml:"access_key"`
+	SecretKey    string `yaml:"secret_key"`
+	SessionToken string `yaml:"session_token"`
+	Region       string `yaml:"region"`
+	Service      string `yaml:"service"`
+}
+
+// UnmarshalYAML accepts "none" or a mapping with exactly one kind of auth
+func (a *Auth) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value != "none" {
+			return fmt.Errorf("line %d: auth must be none or a mapping such as {bearer: token}", value.Line)
+		}
+		*a = Auth{None: true}
+		return nil
+	}
+
+	type plain Auth
+	var auth plain
+	if err := value.Decode(&auth); err != nil {
+		return err
+	}
+	*a = Auth(auth)
+
+	set := 0
+	for _, isSet := range []bool{a.Basic != nil, a.Bearer != "", a.APIKey != nil, a.Digest != nil, a.OAuth2 != nil, a.HMAC != nil, a.AWSSigV4 != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("line %d: auth must set exactly one of basic, bearer, api_key, digest, oauth2, hmac and aws_sigv4", value.Line)
+	}
+
+	switch {
+	case a.APIKey != nil && (a.APIKey.Header == "") == (a.APIKey.Query == ""):
+		return fmt.Errorf("line %d: api_key must set one of header and query", value.Line)
+	case a.OAuth2 != nil && a.OAuth2.TokenURL == "":
+		return fmt.Errorf("line %d: oauth2 needs a token_url", value.Line)
+	case a.OAuth2 != nil && a.OAuth2.ClientAuth != "" && a.OAuth2.ClientAuth != "basic" && a.OAuth2.ClientAuth != "body":
+		return fmt.Errorf("line %d: oauth2 client_auth must be basic or body", value.Line)
+	case a.HMAC != nil && a.HMAC.Key == "":
+		return fmt.Errorf("line %d: hmac needs a key", value.Line)
+	case a.HMAC != nil && !slices.Contains([]string{"", "sha1", "sha256", "sha512"}, a.HMAC.Algorithm):
+		return fmt.Errorf("line %d: hmac algorithm must be sha1, sha256 or sha512", value.Line)
+	case a.HMAC != nil && !slices.Contains([]string{"", "hex", "base64"}, a.HMAC.Encoding):
+		return fmt.Errorf("line %d: hmac encoding must be hex or base64", value.Line)
+	case a.AWSSigV4 != nil && (a.AWSSigV4.AccessKey == "" || a.AWSSigV4.SecretKey == "" || a.AWSSigV4.Region == "" || a.AWSSigV4.Service == ""):
+		return fmt.Errorf("line %d: aws_sigv4 needs access_key, secret_key, region and service", value.Line)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Multipart is the parts of a multipart/form-data body, in the order written
+type Multipart []Part
+
+// Part is a form field or, when File is set, a file upload
+type Part struct {
+	Name  string
+	Value string
+
+	// File is the path of the file to upload, relative to the suite
+	File string
+
+	// Filename and ContentType default to the file's base name and a type
+	// guessed from its extension
+	Filename    string
+	ContentType string
+}
+
+// UnmarshalYAML reads a mapping of part names to field values or to file
+// parts ({file: ./avatar.png, filename: ..., content_type: ...}), keeping
+// the key order
+func (m *Multipart) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: multipart must map part names to values or files", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, val := value.Content[i], value.Content[i+1]
+		part := Part{Name: key.Value}
+
+		switch val.Kind {
+		case yaml.ScalarNode:
+			part.Value = scalarValue(val)
+		case yaml.MappingNode:
+			var file struct {
+				File        string `yaml:"file"`
+				Filename    string `yaml:"filename"`
+				ContentType string `yaml:"content_type"`
+			}
+			if err := val.Decode(&file); err != nil {
+				return err
+			}
+			if file.File == "" {
+				return fmt.Errorf("line %d: multipart part %q needs a file", val.Line, key.Value)
+			}
+			part.File, part.Filename, part.ContentType = file.File, file.Filename, file.ContentType
+		default:
+			return fmt.Errorf("line %d: multipart part %q must be a value or a file", val.Line, key.Value)
+		}
+
+		*m = append(*m, part)
+	}
+	return nil
+}
+
+// bodyFields names the request's body fields that are set
+func (r Request) bodyFields() []string {
+	var set []string
+	if r.Body != nil {
+		set = append(set, "body")
+	}
+	if r.BodyRaw != "" {
+		set = append(set, "body_raw")
+	}
+	if r.Form != nil {
+		set = append(set, "form")
+	}
+	if r.Multipart != nil {
+		set = append(set, "multipart")
+	}
+	if r.BodyFile != "" {
+		set = append(set, "body_file")
+	}
+	return set
+}
+
+// CheckBody reports an error when the request sets more than one kind of body
+func (r Request) CheckBody() error {
+	if set := r.bodyFields(); len(set) > 1 {
+		return fmt.Errorf("only one of body, body_raw, form, multipart and body_file may be set, got %v", set)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dataset is the rows a data-driven test is expanded over, written in YAML
+// either as a list of rows or as the path of a CSV, JSON or YAML file
+type Dataset struct {
+	// File is the dataset file, relative to the suite; the loader reads it into Rows
+	File string
+	Rows []Row
+}
+
+// Row is one set of values for a data-driven test, in column order
+type Row []Column
+
+// Column is a named value in a dataset row
+type Column struct {
+	Name  string
+	Value string
+}
+
+// UnmarshalYAML accepts a file path or a list of rows
+func (d *Dataset) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		d.File = value.Value
+		return nil
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			var row Row
+			if err := item.Decode(&row); err != nil {
+				return err
+			}
+			d.Rows = append(d.Rows, row)
+		}
+		return nil
+	}
+	return fmt.Errorf("line %d: each must be a list of rows or a file path", value.Line)
+}
+
+// UnmarshalYAML reads a mapping of scalar values, keeping the key order
+func (r *Row) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: each row must be a mapping of names to values", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, val := value.Content[i], value.Content[i+1]
+		if val.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: value of %q must be a scalar", val.Line, key.Value)
+		}
+		*r = append(*r, Column{Name: key.Value, Value: scalarValue(val)})
+	}
+	return nil
+}
+
+// Matrix expands a test over every combination of its axes' values
+type Matrix []MatrixAxis
+
+// MatrixAxis is one variable of a matrix and the values it takes
+type MatrixAxis struct {
+	Name   string
+	Values []string
+}
+
+// UnmarshalYAML reads a mapping of names to value lists, keeping the key order
+func (m *Matrix) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: matrix must map names to lists of values", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, list := value.Content[i], value.Content[i+1]
+		if list.Kind != yaml.SequenceNode || len(list.Content) == 0 {
+			return fmt.Errorf("line %d: matrix %q must be a non-empty list of values", list.Line, key.Value)
+		}
+		axis := MatrixAxis{Name: key.Value}
+		for _, item := range list.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: matrix %q values must be scalars", item.Line, key.Value)
+			}
+			axis.Values = append(axis.Values, scalarValue(item))
+		}
+		*m = append(*m, axis)
+	}
+	return nil
+}
+
+// Rows returns every combination of the matrix values, varying the last
+// axis fastest
+func (m Matrix) Rows() []Row {
+	rows := []Row{nil}
+	for _, axis := range m {
+		var next []Row
+		for _, row := range rows {
+			for _, v := range axis.Values {
+				combined := append(Row{}, row...)
+				next = append(next, append(combined, Column{Name: axis.Name, Value: v}))
+			}
+		}
+		rows = next
+	}
+	return rows
+}
+
+// scalarValue returns a scalar as written, with null as the empty string
+func scalarValue(n *yaml.Node) string {
+	if n.Tag == "!!null" {
+		return ""
+	}
+	return n.Value
+}
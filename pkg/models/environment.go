@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithEnvironment returns a copy of the suite whose env has the named
+// environment layered over the base env block, and vars over both. An empty
+// name applies only vars.
+func (s *TestSuite) WithEnvironment(name string, vars map[string]string) (*TestSuite, error) {
+	if name == "" && len(vars) == 0 {
+		return s, nil
+	}
+
+	env := make(map[string]string, len(s.Env))
+	for k, v := range s.Env {
+		env[k] = v
+	}
+
+	if name != "" {
+		profile, ok := s.Environments[name]
+		if !ok {
+			return nil, fmt.Errorf("environment %q is not defined%s", name, s.environmentNames())
+		}
+		for k, v := range profile {
+			env[k] = v
+		}
+	}
+
+	for k, v := range vars {
+		env[k] = v
+	}
+
+	layered := *s
+	layered.Env = env
+	return &layered, nil
+}
+
+// environmentNames lists the defined environments for error messages
+func (s *TestSuite) environmentNames() string {
+	if len(s.Environments) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(s.Environments))
+	for name := range s.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return " (available: " + strings.Join(names, ", ") + ")"
+}
@@ -0,0 +1,87 @@
+package models
+
+import "fmt"
+
+// Prerequisites resolves each test's depends_on names to indices into Tests.
+// The returned slice is parallel to Tests.
+func (s *TestSuite) Prerequisites() ([][]int, error) {
+	index := make(map[string]int, len(s.Tests))
+	duplicates := make(map[string]bool)
+	for i, test := range s.Tests {
+		if _, exists := index[test.Name]; exists {
+			duplicates[test.Name] = true
+		}
+		index[test.Name] = i
+	}
+
+	prereqs := make([][]int, len(s.Tests))
+	for i, test := range s.Tests {
+		for _, dep := range test.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("test %q depends on unknown test %q", test.Name, dep)
+			}
+			if duplicates[dep] {
+				return nil, fmt.Errorf("test %q depends on %q, which names more than one test", test.Name, dep)
+			}
+			if j == i {
+				return nil, fmt.Errorf("test %q depends on itself", test.Name)
+			}
+			prereqs[i] = append(prereqs[i], j)
+		}
+	}
+
+	return prereqs, nil
+}
+
+// CheckDependencies validates depends_on references and reports the first
+// dependency cycle found
+func (s *TestSuite) CheckDependencies() error {
+	prereqs, err := s.Prerequisites()
+	if err != nil {
+		return err
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(s.Tests))
+	var path []int
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := ""
+			for k := len(path) - 1; k >= 0; k-- {
+				cycle = fmt.Sprintf("%q -> ", s.Tests[path[k]].Name) + cycle
+				if path[k] == i {
+					break
+				}
+			}
+			return fmt.Errorf("dependency cycle: %s%q", cycle, s.Tests[i].Name)
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+		for _, j := range prereqs[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range s.Tests {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HTTPOptions configure the client that sends requests. Set on the suite
+// they apply to every test; a test's own options override them one by one.
+type HTTPOptions struct {
+	// FollowRedirects is true (the default, up to 10), false, or the most
+	// redirects to follow
+	FollowRedirects *Redirects `yaml:"follow_redirects"`
+
+	// Proxy is the URL of the proxy to use, or "none" to connect directly.
+	// Without one, HTTP_PROXY, HTTPS_PROXY and NO_PROXY apply.
+	Proxy string `yaml:"proxy"`
+
+	// InsecureSkipVerify accepts any server certificate
+	InsecureSkipVerify *bool `yaml:"insecure_skip_verify"`
+
+	// CACert is a PEM bundle of CAs trusted alongside the system's
+	CACert string `yaml:"ca_cert"`
+
+	// ClientCert and ClientKey are the PEM certificate and key for mutual TLS
+	ClientCert string `yaml:"client_cert"`
+	ClientKey  string `yaml:"client_key"`
+
+	// ServerName overrides the name sent in SNI and checked against the
+	// server's certificate
+	ServerName string `yaml:"server_name"`
+}
+
+// NoProxy is the proxy setting for connecting directly
+const NoProxy = "none"
+
+// Redirects is how many redirects to follow
+type Redirects struct {
+	Follow bool
+	Max    int // 0 for the default limit
+}
+
+// UnmarshalYAML accepts a bool or a maximum number of redirects
+func (r *Redirects) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		// A number is a maximum, even 1 or 0, which ParseBool would take
+		if n, err := strconv.Atoi(value.Value); err == nil && n >= 0 {
+			*r = Redirects{Follow: n > 0, Max: n}
+			return nil
+		}
+		var follow bool
+		if value.ShortTag() == "!!bool" && value.Decode(&follow) == nil {
+			*r = Redirects{Follow: follow}
+			return nil
+		}
+	}
+	return fmt.Errorf("line %d: follow_redirects must be true, false or a number of redirects", value.Line)
+}
+
+// Merge returns o with the options set in override replacing its own
+func (o *HTTPOptions) Merge(override *HTTPOptions) *HTTPOptions {
+	if o == nil {
+		return override
+	}
+	if override == nil {
+		return o
+	}
+	merged := *o
+	if override.FollowRedirects != nil {
+		merged.FollowRedirects = override.FollowRedirects
+	}
+	if override.Proxy != "" {
+		merged.Proxy = override.Proxy
+	}
+	if override.InsecureSkipVerify != nil {
+		merged.InsecureSkipVerify = override.InsecureSkipVerify
+	}
+	if override.CACert != "" {
+		merged.CACert = override.CACert
+	}
+	if override.ClientCert != "" {
+		merged.ClientCert, merged.ClientKey = override.ClientCert, override.ClientKey
+	}
+	if override.ServerName != "" {
+		merged.ServerName = override.ServerName
+	}
+	return &merged
+}
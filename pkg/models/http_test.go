@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRedirectsUnmarshal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Redirects
+		wantErr bool
+	}{
+		{"true", Redirects{Follow: true}, false},
+		{"false", Redirects{Follow: false}, false},
+		{"1", Redirects{Follow: true, Max: 1}, false},
+		{"0", Redirects{Follow: false, Max: 0}, false},
+		{"5", Redirects{Follow: true, Max: 5}, false},
+		{"-1", Redirects{}, true},
+		{"\"true\"", Redirects{}, true},
+		{"maybe", Redirects{}, true},
+	}
+
+	for _, tt := range tests {
+		var got struct {
+			Follow Redirects `yaml:"follow_redirects"`
+		}
+		err := yaml.Unmarshal([]byte("follow_redirects: "+tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Follow != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.in, got.Follow, tt.want)
+		}
+	}
+}
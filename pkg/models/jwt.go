@@ -0,0 +1,40 @@
+package models
+
+// JWT is a token the suite mints when it starts, available to its tests as
+// a variable of the same name
+type JWT struct {
+	// Algorithm is HS256, RS256, ES256 or another of their family
+	Algorithm string `yaml:"algorithm"`
+
+	// Key is the HMAC secret, or a PEM private key for RS and ES algorithms
+	Key string `yaml:"key"`
+
+	// Header adds fields such as kid to the token's header
+	Header map[string]interface{} `yaml:"header"`
+
+	// Claims may use {{ }} expressions; a claim that is exactly one keeps
+	// its type
+	Claims map[string]interface{} `yaml:"claims"`
+
+	// ExpiresIn sets exp this long after the token is minted, unless the
+	// claims set exp themselves
+	ExpiresIn Duration `yaml:"expires_in"`
+}
+
+// JWTExpect decodes a token from the response and checks it
+type JWTExpect struct {
+	// From is where the token is: a JSON path into the body (optionally
+	// prefixed json:), or header:<Name>, whose "Bearer " prefix is ignored
+	From string `yaml:"from"`
+
+	// Algorithm is the alg the token must be signed with
+	Algorithm string `yaml:"algorithm"`
+
+	// Key, when set, verifies the signature: the HMAC secret, or a PEM public
+	// key or certificate. Verified tokens must also be within exp and nbf.
+	Key string `yaml:"key"`
+
+	// Claims maps a path into the claims to an exact value or a matcher, as
+	// in Expect.JSON
+	Claims map[string]interface{} `yaml:"claims"`
+}
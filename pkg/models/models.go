@@ -1,23 +1,209 @@
 package models
 
 type TestSuite struct {
-	Env   map[string]string `yaml:"env"`
+	// Name labels the suite's results. When several suites run together,
+	// unnamed ones are labelled with their file path.
+	Name string `yaml:"name"`
+
+	Env map[string]string `yaml:"env"`
+
+	// Environments are named sets of variables layered over Env when
+	// selected, e.g. with probe run --env staging
+	Environments map[string]map[string]string `yaml:"environments"`
+
+	// Secrets names variables, from env or captures, whose values are
+	// masked in every report, stored result and API response
+	Secrets []string `yaml:"secrets"`
+
+	Config SuiteConfig `yaml:"config"`
+
+	// Auth authenticates every test that doesn't set its own
+	Auth *Auth `yaml:"auth"`
+
+	// JWT names tokens to mint when the suite starts
+	JWT map[string]JWT `yaml:"jwt"`
+
+	// HTTP configures redirects, proxying and TLS for every test
+	HTTP *HTTPOptions `yaml:"http"`
+
+	// Session gives the suite a cookie jar shared by all its tests, so
+	// cookies set by one (a login, say) are sent by the rest
+	Session bool `yaml:"session"`
+
+	// Sequential runs tests one at a time in file order (dependencies permitting)
+	Sequential bool `yaml:"sequential"`
+
+	// Setup runs in order before the tests; values it captures are visible to them
+	Setup []TestCase `yaml:"setup"`
+
 	Tests []TestCase
+
+	// Teardown runs in order after the tests, even if they fail or the run is cancelled
+	Teardown []TestCase `yaml:"teardown"`
+}
+
+// SuiteConfig holds suite-wide execution settings
+type SuiteConfig struct {
+	// Timeout is the default per-request timeout for tests that don't set their own
+	Timeout Duration `yaml:"timeout"`
+
+	// SuiteTimeout bounds the whole run; tests still pending when it expires are marked timed out
+	SuiteTimeout Duration `yaml:"suite_timeout"`
+
+	// Retry is the default retry policy for tests that don't set their own
+	Retry *RetryPolicy `yaml:"retry"`
+}
+
+// RetryPolicy re-runs a failing test. With neither OnStatus nor OnError set,
+// any failure is retried.
+type RetryPolicy struct {
+	// Attempts is the total number of tries, including the first
+	Attempts int `yaml:"attempts"`
+
+	// Backoff is the delay before the first retry, doubled for each one after
+	Backoff Duration `yaml:"backoff"`
+
+	// OnStatus retries only when the response has one of these status codes
+	OnStatus []int `yaml:"on_status"`
+
+	// OnError retries when the request itself fails (connection errors, timeouts)
+	OnError bool `yaml:"on_error"`
 }
 
 type TestCase struct {
 	Name    string  `yaml:"name"`
 	Request Request `yaml:"request"`
 	Expect  Expect  `yaml:"expect"`
+
+	// Capture maps a variable name to the response value it is taken from:
+	// a JSON path into the body, "header:<Name>" or "status".
+	Capture map[string]string `yaml:"capture"`
+
+	// DependsOn lists the names of tests that must pass before this one runs
+	DependsOn []string `yaml:"depends_on"`
+
+	// Tags group tests so runs can select or exclude them
+	Tags []string `yaml:"tags"`
+
+	// Timeout overrides the suite's request timeout for this test
+	Timeout Duration `yaml:"timeout"`
+
+	// Retry overrides the suite's retry policy for this test
+	Retry *RetryPolicy `yaml:"retry"`
+
+	// Until re-sends the request until its conditions hold, for polling async operations
+	Until *Until `yaml:"until"`
+
+	// Each and Matrix expand the test into one copy per dataset row or value
+	// combination when the suite is loaded
+	Each   *Dataset `yaml:"each"`
+	Matrix Matrix   `yaml:"matrix"`
+
+	// Vars holds the row an expanded test was made from; its values take
+	// precedence over the suite env
+	Vars map[string]string `yaml:"-"`
+
+	// Auth overrides the suite's auth for this test
+	Auth *Auth `yaml:"auth"`
+
+	// HTTP overrides the suite's client options for this test
+	HTTP *HTTPOptions `yaml:"http"`
+
+	// Session names the cookie jar the test shares with others in the same
+	// session; tests without one use the suite's jar if it sets session: true,
+	// and NoSession opts out of it
+	Session string `yaml:"session"`
+}
+
+// NoSession is the session of a test that sends no cookies but its own,
+// even in a suite with session: true
+const NoSession = "none"
+
+// Until is a set of expectations the response must meet before the test's
+// own expectations are checked. A zero Status accepts any status code.
+type Until struct {
+	Expect `yaml:",inline"`
+
+	// Interval is the delay between polls (default 1s)
+	Interval Duration `yaml:"interval"`
+
+	// MaxWait is how long to keep polling before failing (default 30s)
+	MaxWait Duration `yaml:"max_wait"`
 }
 
 type Request struct {
-	Method  string                 `yaml:"method"`
-	Path    string                 `yaml:"path"`
-	Headers map[string]string      `yaml:"headers"`
-	Body    map[string]interface{} `yaml:"body"`
+	Method string `yaml:"method"`
+
+	// Path is appended to the suite's base_url, unless it is an absolute
+	// http:// or https:// URL
+	Path string `yaml:"path"`
+
+	// Query parameters are URL-encoded after substitution and added to any
+	// query string already in Path
+	Query Query `yaml:"query"`
+
+	Headers map[string]string `yaml:"headers"`
+
+	// Cookies are sent with the request, alongside any from the test's session
+	Cookies map[string]string `yaml:"cookies"`
+
+	// At most one of the body fields below is set. Each defaults the
+	// Content-Type header unless Headers sets it.
+
+	// Body is sent as JSON: an object, an array or a scalar
+	Body interface{} `yaml:"body"`
+
+	// BodyRaw is sent as written, as text/plain
+	BodyRaw string `yaml:"body_raw"`
+
+	// Form is sent URL-encoded, as application/x-www-form-urlencoded
+	Form map[string]string `yaml:"form"`
+
+	// Multipart is sent as multipart/form-data
+	Multipart Multipart `yaml:"multipart"`
+
+	// BodyFile is streamed from disk, relative to the suite; the
+	// Content-Type is guessed from its extension
+	BodyFile string `yaml:"body_file"`
 }
 type Expect struct {
 	Status int                    `yaml:"status"`
 	JSON   map[string]interface{} `yaml:"json"`
+
+	// Headers maps a header name (case-insensitive) to an exact value or a matcher
+	Headers map[string]interface{} `yaml:"headers"`
+
+	// Cookies maps the name of a cookie the response sets to an exact value or a matcher
+	Cookies map[string]interface{} `yaml:"cookies"`
+
+	// RedirectTo is where the response redirects: an exact URL, a path
+	// (compared with the target's path and query) or a matcher on the URL
+	RedirectTo interface{} `yaml:"redirect_to"`
+
+	// JWT decodes a token in the response and checks its claims and signature
+	JWT *JWTExpect `yaml:"jwt"`
+
+	// Body checks the raw response text, for endpoints that don't return JSON
+	Body *BodyExpect `yaml:"body"`
+
+	// MaxDuration fails the test when the response takes longer than this
+	MaxDuration Duration `yaml:"max_duration"`
+}
+
+// BodyExpect describes assertions on the raw response body
+type BodyExpect struct {
+	Exact       *string  `yaml:"exact"`
+	Contains    []string `yaml:"contains"`
+	NotContains []string `yaml:"not_contains"`
+	Regex       string   `yaml:"regex"`
+}
+
+// HasTag reports whether the test is tagged with tag
+func (t TestCase) HasTag(tag string) bool {
+	for _, own := range t.Tags {
+		if own == tag {
+			return true
+		}
+	}
+	return false
 }
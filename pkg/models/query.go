@@ -0,0 +1,36 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Query is a request's query parameters. A name maps to one value or,
+// written as a list, to several that are sent as repeated keys.
+type Query map[string][]string
+
+// UnmarshalYAML reads a mapping of names to scalars or lists of scalars
+func (q *Query) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: query must map parameter names to values", value.Line)
+	}
+	*q = make(Query, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, val := value.Content[i], value.Content[i+1]
+		switch val.Kind {
+		case yaml.ScalarNode:
+			(*q)[key.Value] = append((*q)[key.Value], scalarValue(val))
+		case yaml.SequenceNode:
+			for _, item := range val.Content {
+				if item.Kind != yaml.ScalarNode {
+					return fmt.Errorf("line %d: values of query %q must be scalars", item.Line, key.Value)
+				}
+				(*q)[key.Value] = append((*q)[key.Value], scalarValue(item))
+			}
+		default:
+			return fmt.Errorf("line %d: query %q must be a value or a list of values", val.Line, key.Value)
+		}
+	}
+	return nil
+}
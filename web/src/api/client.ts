@@ -54,11 +54,11 @@ export interface RunResponse {
   passed_tests: number;
   failed_tests: number;
   results: {
-    Name: string;
-    Passed: boolean;
-    StatusCode: number;
-    Error: string | null;
-    Duration: number;
+    name: string;
+    passed: boolean;
+    status_code?: number;
+    error?: string;
+    duration: string;
   }[];
 }
 
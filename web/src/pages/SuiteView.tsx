@@ -102,23 +102,23 @@ export default function SuiteView() {
               <div
                 key={i}
                 className={`flex items-center justify-between bg-[var(--bg-secondary)] border rounded px-4 py-3 ${
-                  r.Passed ? 'border-[var(--border)]' : 'border-[var(--danger)]/30'
+                  r.passed ? 'border-[var(--border)]' : 'border-[var(--danger)]/30'
                 }`}
               >
                 <div className="flex items-center gap-3">
-                  <span className={`w-1.5 h-1.5 rounded-full ${r.Passed ? 'bg-[var(--success)]' : 'bg-[var(--danger)]'}`} />
-                  <span className="text-xs font-medium">{r.Name}</span>
+                  <span className={`w-1.5 h-1.5 rounded-full ${r.passed ? 'bg-[var(--success)]' : 'bg-[var(--danger)]'}`} />
+                  <span className="text-xs font-medium">{r.name}</span>
                 </div>
                 <div className="flex items-center gap-4 text-xs text-[var(--text-tertiary)] font-['JetBrains_Mono']">
-                  {r.StatusCode > 0 && (
+                  {r.status_code !== undefined && r.status_code > 0 && (
                     <span className="bg-[var(--bg-tertiary)] px-2 py-0.5 rounded text-[10px]">
-                      {r.StatusCode}
+                      {r.status_code}
                     </span>
                   )}
-                  <span>{(r.Duration / 1_000_000).toFixed(0)}ms</span>
-                  {r.Error && (
+                  <span>{r.duration}</span>
+                  {r.error && (
                     <span className="text-[var(--danger)] text-[10px] max-w-[200px] truncate">
-                      {r.Error}
+                      {r.error}
                     </span>
                   )}
                 </div>